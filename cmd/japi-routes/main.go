@@ -0,0 +1,65 @@
+// Command japi-routes prints the route table of a running japi-core server, read from the
+// endpoint enabled with handler.WithDebugRoutes.
+//
+// Usage:
+//
+//	japi-routes -url http://localhost:8080/_debug/routes -token "$DEBUG_TOKEN" -format table
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:8080"+handler.DebugRoutesPath, "URL of the debug routes endpoint")
+	token := flag.String("token", os.Getenv("JAPI_DEBUG_TOKEN"), "Bearer token for the endpoint (default $JAPI_DEBUG_TOKEN)")
+	format := flag.String("format", string(handler.RouteFormatTable), "Output format: table or json")
+	timeout := flag.Duration("timeout", 10*time.Second, "Request timeout")
+	flag.Parse()
+
+	routes, err := fetchRoutes(*url, *token, *timeout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "japi-routes:", err)
+		os.Exit(1)
+	}
+	if err := handler.WriteRoutes(os.Stdout, routes, handler.RouteFormat(*format)); err != nil {
+		fmt.Fprintln(os.Stderr, "japi-routes:", err)
+		os.Exit(1)
+	}
+}
+
+// fetchRoutes reads the JSON route table from the endpoint
+func fetchRoutes(url, token string, timeout time.Duration) ([]handler.RouteDescription, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// The endpoint answers 404 to requests its guard rejects
+		return nil, fmt.Errorf("GET %s: %s (is WithDebugRoutes enabled and the token correct?)", url, resp.Status)
+	}
+	var routes []handler.RouteDescription
+	if err := json.NewDecoder(resp.Body).Decode(&routes); err != nil {
+		return nil, fmt.Errorf("decoding route table: %w", err)
+	}
+	return routes, nil
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ErrorDefinition describes a stable, machine-readable API error
+type ErrorDefinition struct {
+	Code        string `json:"code"`                  // Stable error code (e.g. "user.email_taken")
+	Status      int    `json:"status"`                // HTTP status returned with this code
+	Message     string `json:"message"`               // Default human-readable message
+	Description string `json:"description,omitempty"` // Optional: longer explanation for API consumers
+}
+
+// ErrorCatalog holds the error definitions an application can return.
+// It is served as JSON (it implements http.Handler) and read by the swagger generator.
+type ErrorCatalog struct {
+	definitions map[string]ErrorDefinition
+	mu          sync.RWMutex
+}
+
+// DefaultErrorCatalog is the catalog used when no explicit catalog is supplied.
+// It starts out with the codes japi-core itself returns (see FrameworkErrors).
+var DefaultErrorCatalog = NewErrorCatalog()
+
+// FrameworkErrors are the error codes returned by japi-core itself: PostgreSQL errors translated by
+// PgErrorTranslator, failed preconditions and invalid page cursors. Statuses are the defaults;
+// PgErrorTranslator.WithForeignKeyStatus and constraint mappings can change them per application.
+var FrameworkErrors = []ErrorDefinition{
+	{Code: "db.unique_violation", Status: http.StatusConflict, Message: "Resource already exists"},
+	{Code: "db.foreign_key_violation", Status: http.StatusConflict, Message: "Referenced resource does not exist or is still in use"},
+	{Code: "db.not_null_violation", Status: http.StatusUnprocessableEntity, Message: "Required value is missing"},
+	{Code: "db.check_violation", Status: http.StatusUnprocessableEntity, Message: "Value violates a constraint"},
+	{Code: "db.integrity_violation", Status: http.StatusConflict, Message: "Integrity constraint violation"},
+	{Code: "db.retryable", Status: http.StatusServiceUnavailable, Message: "Concurrent update conflict, please retry",
+		Description: "A serialization failure or deadlock; retry after the Retry-After delay"},
+	{Code: "db.query_canceled", Status: http.StatusGatewayTimeout, Message: "Database query timed out"},
+	{Code: "db.error", Status: StatusDatabaseError, Message: "Database error"},
+	{Code: "precondition.failed", Status: http.StatusPreconditionFailed, Message: "Precondition Failed",
+		Description: "The resource has been modified since it was retrieved"},
+	{Code: "pagination.invalid_cursor", Status: http.StatusBadRequest, Message: "Invalid cursor"},
+}
+
+func init() {
+	DefaultErrorCatalog.MustRegister(FrameworkErrors...)
+}
+
+// NewErrorCatalog creates an empty error catalog
+func NewErrorCatalog() *ErrorCatalog {
+	return &ErrorCatalog{
+		definitions: make(map[string]ErrorDefinition),
+	}
+}
+
+// Register adds error definitions to the catalog.
+// Re-registering an identical definition is a no-op; a conflicting definition for an existing code is an error.
+// All definitions are validated first, so on error none of them are added.
+func (c *ErrorCatalog) Register(defs ...ErrorDefinition) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending := make(map[string]ErrorDefinition, len(defs))
+	for _, def := range defs {
+		if def.Code == "" {
+			return fmt.Errorf("error definition must have a code")
+		}
+		if def.Status < 400 || def.Status > 599 {
+			return fmt.Errorf("error definition %q has invalid status %d", def.Code, def.Status)
+		}
+		existing, exists := c.definitions[def.Code]
+		if !exists {
+			existing, exists = pending[def.Code]
+		}
+		if exists && existing != def {
+			return fmt.Errorf("error code %q is already registered with a different definition", def.Code)
+		}
+		pending[def.Code] = def
+	}
+
+	for code, def := range pending {
+		c.definitions[code] = def
+	}
+	return nil
+}
+
+// MustRegister is like Register but panics on error. Intended for package initialization.
+func (c *ErrorCatalog) MustRegister(defs ...ErrorDefinition) {
+	if err := c.Register(defs...); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the definition for an error code
+func (c *ErrorCatalog) Lookup(code string) (ErrorDefinition, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	def, ok := c.definitions[code]
+	return def, ok
+}
+
+// Definitions returns all registered definitions sorted by code
+func (c *ErrorCatalog) Definitions() []ErrorDefinition {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	defs := make([]ErrorDefinition, 0, len(c.definitions))
+	for _, def := range c.definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Code < defs[j].Code })
+	return defs
+}
+
+// New creates an APIError from a registered definition.
+// An unregistered code yields a 500 so the mistake is visible rather than silently mis-reported.
+func (c *ErrorCatalog) New(code string, detail ...string) *APIError {
+	def, ok := c.Lookup(code)
+	if !ok {
+		return NewAPIError(http.StatusInternalServerError, "Internal Server Error",
+			fmt.Sprintf("unregistered error code %q", code))
+	}
+
+	apiErr := NewAPIError(def.Status, def.Message, detail...)
+	apiErr.ErrorCode = def.Code
+	return apiErr
+}
+
+// ServeHTTP writes the catalog as JSON so clients can discover every error code.
+//
+// Example:
+//
+//	r.Method(http.MethodGet, "/api/errors", core.DefaultErrorCatalog)
+func (c *ErrorCatalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	Success(w, map[string]any{
+		"errors": c.Definitions(),
+	})
+}
+
+// RegisterErrors adds definitions to DefaultErrorCatalog, panicking on conflicts
+func RegisterErrors(defs ...ErrorDefinition) {
+	DefaultErrorCatalog.MustRegister(defs...)
+}
+
+// NewCodedError creates an APIError from a definition in DefaultErrorCatalog
+func NewCodedError(code string, detail ...string) *APIError {
+	return DefaultErrorCatalog.New(code, detail...)
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TestErrorCatalogRegister verifies definitions are validated and conflicts are rejected
+func TestErrorCatalogRegister(t *testing.T) {
+	emailTaken := ErrorDefinition{Code: "user.email_taken", Status: http.StatusConflict, Message: "Email is already registered"}
+
+	t.Run("identical re-registration is a no-op", func(t *testing.T) {
+		catalog := NewErrorCatalog()
+		if err := catalog.Register(emailTaken); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := catalog.Register(emailTaken); err != nil {
+			t.Errorf("Expected re-registering the same definition to succeed, got %v", err)
+		}
+		if defs := catalog.Definitions(); len(defs) != 1 {
+			t.Errorf("Expected 1 definition, got %v", defs)
+		}
+	})
+
+	t.Run("conflicting definition", func(t *testing.T) {
+		catalog := NewErrorCatalog()
+		catalog.MustRegister(emailTaken)
+		conflicting := emailTaken
+		conflicting.Status = http.StatusUnprocessableEntity
+		err := catalog.Register(conflicting)
+		if err == nil || !strings.Contains(err.Error(), "user.email_taken") {
+			t.Errorf("Expected a conflict error naming the code, got %v", err)
+		}
+		if def, _ := catalog.Lookup("user.email_taken"); def.Status != http.StatusConflict {
+			t.Errorf("Expected the original definition to be kept, got %+v", def)
+		}
+	})
+
+	t.Run("invalid definitions", func(t *testing.T) {
+		catalog := NewErrorCatalog()
+		if err := catalog.Register(ErrorDefinition{Status: http.StatusBadRequest}); err == nil {
+			t.Error("Expected an error for a missing code")
+		}
+		if err := catalog.Register(ErrorDefinition{Code: "ok", Status: http.StatusOK}); err == nil {
+			t.Error("Expected an error for a non-error status")
+		}
+	})
+
+	t.Run("rejected batches add nothing", func(t *testing.T) {
+		catalog := NewErrorCatalog()
+		if err := catalog.Register(emailTaken, ErrorDefinition{Code: "user.invalid", Status: http.StatusOK}); err == nil {
+			t.Fatal("Expected an error for the invalid definition")
+		}
+		conflicting := emailTaken
+		conflicting.Message = "Other"
+		if err := catalog.Register(emailTaken, conflicting); err == nil {
+			t.Fatal("Expected an error for conflicting definitions in one batch")
+		}
+		if defs := catalog.Definitions(); len(defs) != 0 {
+			t.Errorf("Expected no definitions after rejected batches, got %v", defs)
+		}
+	})
+
+	t.Run("MustRegister panics on conflict", func(t *testing.T) {
+		catalog := NewErrorCatalog()
+		catalog.MustRegister(emailTaken)
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected MustRegister to panic")
+			}
+		}()
+		catalog.MustRegister(ErrorDefinition{Code: "user.email_taken", Status: http.StatusBadRequest, Message: "Other"})
+	})
+}
+
+// TestErrorCatalogNew verifies APIErrors are built from definitions
+func TestErrorCatalogNew(t *testing.T) {
+	catalog := NewErrorCatalog()
+	catalog.MustRegister(ErrorDefinition{Code: "user.not_found", Status: http.StatusNotFound, Message: "User not found"})
+
+	apiErr := catalog.New("user.not_found", "no user with id 7")
+	if apiErr.Code != http.StatusNotFound || apiErr.Message != "User not found" ||
+		apiErr.ErrorCode != "user.not_found" || apiErr.Detail != "no user with id 7" {
+		t.Errorf("Unexpected error %+v", apiErr)
+	}
+
+	unknown := catalog.New("user.missing")
+	if unknown.Code != http.StatusInternalServerError || unknown.ErrorCode != "" ||
+		!strings.Contains(unknown.Detail, "user.missing") {
+		t.Errorf("Expected a 500 naming the unregistered code, got %+v", unknown)
+	}
+}
+
+// TestErrorCatalogServeHTTP verifies the catalog is served as JSON sorted by code
+func TestErrorCatalogServeHTTP(t *testing.T) {
+	catalog := NewErrorCatalog()
+	catalog.MustRegister(
+		ErrorDefinition{Code: "user.not_found", Status: http.StatusNotFound, Message: "User not found"},
+		ErrorDefinition{Code: "auth.expired", Status: http.StatusUnauthorized, Message: "Token expired", Description: "Refresh the token"},
+	)
+
+	rec := httptest.NewRecorder()
+	catalog.ServeHTTP(rec, httptest.NewRequest("GET", "/api/errors", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("Expected 200 JSON, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	var body struct {
+		Errors []ErrorDefinition `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(body.Errors) != 2 || body.Errors[0].Code != "auth.expired" || body.Errors[0].Description != "Refresh the token" ||
+		body.Errors[1].Code != "user.not_found" {
+		t.Errorf("Unexpected catalog %+v", body.Errors)
+	}
+}
+
+// TestDefaultErrorCatalog verifies the package-level helpers use DefaultErrorCatalog
+func TestDefaultErrorCatalog(t *testing.T) {
+	RegisterErrors(ErrorDefinition{Code: "test.default_catalog", Status: http.StatusTeapot, Message: "Teapot"})
+	if apiErr := NewCodedError("test.default_catalog"); apiErr.Code != http.StatusTeapot || apiErr.ErrorCode != "test.default_catalog" {
+		t.Errorf("Unexpected error %+v", apiErr)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected RegisterErrors to panic on a conflict")
+		}
+	}()
+	RegisterErrors(ErrorDefinition{Code: "test.default_catalog", Status: http.StatusConflict, Message: "Teapot"})
+}
+
+// TestDefaultErrorCatalogFrameworkErrors verifies the codes japi-core returns are registered as they are returned
+func TestDefaultErrorCatalogFrameworkErrors(t *testing.T) {
+	translator := NewPgErrorTranslator()
+	returned := []*APIError{
+		CheckPreconditions(preconditionRequest(), ResourceVersion{ETag: StrongETag("v2")}).(*APIError),
+		NewCursorCodec([]byte("secret")).Decode("forged", &struct{}{}).(*APIError),
+	}
+	for _, code := range []string{PgUniqueViolation, PgForeignKeyViolation, PgNotNullViolation, PgCheckViolation,
+		PgSerializationFailure, PgQueryCanceled, "23P01", "42P01"} {
+		apiErr, _ := translator.Translate(&pgconn.PgError{Code: code})
+		returned = append(returned, apiErr)
+	}
+
+	for _, apiErr := range returned {
+		def, ok := DefaultErrorCatalog.Lookup(apiErr.ErrorCode)
+		if !ok {
+			t.Errorf("Expected %q in DefaultErrorCatalog", apiErr.ErrorCode)
+			continue
+		}
+		if def.Status != apiErr.Code || def.Message != apiErr.Message {
+			t.Errorf("Expected %q to be registered as returned (%d %q), got %+v", apiErr.ErrorCode, apiErr.Code, apiErr.Message, def)
+		}
+	}
+}
+
+// preconditionRequest builds a write whose If-Match names a stale version
+func preconditionRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "/docs/1", nil)
+	req.Header.Set("If-Match", `"v1"`)
+	return req
+}
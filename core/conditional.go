@@ -0,0 +1,142 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ResourceVersion identifies the state of a resource for conditional requests
+type ResourceVersion struct {
+	ETag         string    // Entity tag including quotes, e.g. `"42"` or `W/"42"` (see StrongETag, WeakETag)
+	LastModified time.Time // Optional: last modification time, sent as Last-Modified
+}
+
+// Versioned is implemented by responses that supply their own version (e.g. from a row
+// version or updated_at column) instead of having the ETag computed from the encoded body.
+type Versioned interface {
+	ResourceVersion() ResourceVersion
+}
+
+// ETagOptions configures how entity tags are produced for responses
+type ETagOptions struct {
+	// Weak marks computed ETags as weak (W/"..."), for representations that are
+	// semantically equivalent but not byte-identical across encodings
+	Weak bool
+}
+
+// StrongETag quotes value as a strong entity tag
+func StrongETag(value string) string {
+	return `"` + value + `"`
+}
+
+// WeakETag quotes value as a weak entity tag
+func WeakETag(value string) string {
+	return `W/"` + value + `"`
+}
+
+// ComputeETag derives an entity tag from an encoded representation
+func ComputeETag(body []byte, weak bool) string {
+	sum := sha256.Sum256(body)
+	value := hex.EncodeToString(sum[:16])
+	if weak {
+		return WeakETag(value)
+	}
+	return StrongETag(value)
+}
+
+// SetVersionHeaders writes the ETag and Last-Modified headers for version
+func SetVersionHeaders(w http.ResponseWriter, version ResourceVersion) {
+	if version.ETag != "" {
+		w.Header().Set("ETag", version.ETag)
+	}
+	if !version.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", version.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// NotModified reports whether a GET or HEAD request's If-None-Match or If-Modified-Since
+// conditions show the client already holds version (RFC 9110 §13.2.2).
+// If-None-Match uses weak comparison and takes precedence over If-Modified-Since.
+func NotModified(r *http.Request, version ResourceVersion) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return version.ETag != "" && etagListMatches(ifNoneMatch, version.ETag, false)
+	}
+
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !version.LastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !version.LastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// WriteNotModified sends 304 Not Modified with the version headers and no body
+func WriteNotModified(w http.ResponseWriter, version ResourceVersion) {
+	SetVersionHeaders(w, version)
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// CheckPreconditions evaluates If-Match and If-Unmodified-Since against the current version
+// of the resource a write targets (RFC 9110 §13.2.2). It returns a 412 APIError with error
+// code "precondition.failed" when the client's copy is stale, so lost updates are rejected.
+// If-Match uses strong comparison and takes precedence over If-Unmodified-Since. current describes
+// an existing resource, so "If-Match: *" passes even when it has no ETag.
+func CheckPreconditions(r *http.Request, current ResourceVersion) error {
+	failed := NewAPIError(http.StatusPreconditionFailed, "Precondition Failed",
+		"The resource has been modified since it was retrieved").WithCode("precondition.failed")
+
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if !etagListMatches(ifMatch, current.ETag, true) {
+			return failed
+		}
+		return nil
+	}
+
+	if ifUnmodifiedSince := r.Header.Get("If-Unmodified-Since"); ifUnmodifiedSince != "" && !current.LastModified.IsZero() {
+		since, err := http.ParseTime(ifUnmodifiedSince)
+		if err == nil && current.LastModified.Truncate(time.Second).After(since) {
+			return failed
+		}
+	}
+
+	return nil
+}
+
+// HasPreconditions reports whether the request carries If-Match or If-Unmodified-Since
+func HasPreconditions(r *http.Request) bool {
+	return r.Header.Get("If-Match") != "" || r.Header.Get("If-Unmodified-Since") != ""
+}
+
+// etagListMatches reports whether an If-Match/If-None-Match value matches etag.
+// "*" matches any existing representation, with or without an ETag; callers decide whether one exists.
+// Strong comparison never matches weak tags.
+func etagListMatches(header, etag string, strong bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	if etag == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strong {
+			if candidate == etag && !strings.HasPrefix(etag, "W/") {
+				return true
+			}
+			continue
+		}
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
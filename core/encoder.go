@@ -0,0 +1,216 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Encoder writes a response body in a specific media type
+type Encoder interface {
+	// ContentType returns the media type written by this encoder (e.g. "application/json")
+	ContentType() string
+
+	// Encode writes v to w
+	Encode(w io.Writer, v any) error
+}
+
+// encoderFunc adapts a media type and an encode function to the Encoder interface
+type encoderFunc struct {
+	contentType string
+	encode      func(w io.Writer, v any) error
+}
+
+func (e encoderFunc) ContentType() string             { return e.contentType }
+func (e encoderFunc) Encode(w io.Writer, v any) error { return e.encode(w, v) }
+
+// NewEncoder creates an Encoder from a media type and an encode function
+func NewEncoder(contentType string, encode func(w io.Writer, v any) error) Encoder {
+	return encoderFunc{contentType: contentType, encode: encode}
+}
+
+// Built-in encoders
+var (
+	JSONEncoder = NewEncoder("application/json", func(w io.Writer, v any) error {
+		return json.NewEncoder(w).Encode(v)
+	})
+	XMLEncoder         = NewEncoder("application/xml", encodeXML)
+	MessagePackEncoder = NewEncoder("application/msgpack", func(w io.Writer, v any) error {
+		return msgpack.NewEncoder(w).Encode(v)
+	})
+	CBOREncoder = NewEncoder("application/cbor", func(w io.Writer, v any) error {
+		return cbor.NewEncoder(w).Encode(v)
+	})
+)
+
+// xmlItems is the root element XMLEncoder wraps around slices and arrays
+type xmlItems struct {
+	XMLName xml.Name `xml:"items"`
+	Items   any      `xml:"item"`
+}
+
+// encodeXML writes v as XML. Slices and arrays have no element of their own, so they are wrapped
+// in an <items> root with one <item> per element. Values XML cannot represent, such as maps,
+// yield a 406 APIError.
+func encodeXML(w io.Writer, v any) error {
+	if value := reflect.Indirect(reflect.ValueOf(v)); (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) &&
+		value.Type().Elem().Kind() != reflect.Uint8 {
+		v = xmlItems{Items: value.Interface()}
+	}
+
+	err := xml.NewEncoder(w).Encode(v)
+	var unsupported *xml.UnsupportedTypeError
+	if errors.As(err, &unsupported) {
+		return NewAPIError(http.StatusNotAcceptable, "Not Acceptable",
+			"application/xml cannot represent "+unsupported.Type.String())
+	}
+	return err
+}
+
+// EncoderRegistry holds the encoders available for content negotiation.
+// The first registered encoder is the default when the client sends no Accept header.
+type EncoderRegistry struct {
+	encoders []Encoder
+	mu       sync.RWMutex
+}
+
+// DefaultEncoders negotiates between JSON (default), XML, MessagePack and CBOR
+var DefaultEncoders = NewEncoderRegistry(JSONEncoder, XMLEncoder, MessagePackEncoder, CBOREncoder)
+
+// NewEncoderRegistry creates a registry with the given encoders in preference order
+func NewEncoderRegistry(encoders ...Encoder) *EncoderRegistry {
+	return &EncoderRegistry{
+		encoders: append([]Encoder(nil), encoders...),
+	}
+}
+
+// Register adds an encoder, replacing any existing encoder for the same media type
+func (reg *EncoderRegistry) Register(encoder Encoder) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	for i, existing := range reg.encoders {
+		if existing.ContentType() == encoder.ContentType() {
+			reg.encoders[i] = encoder
+			return
+		}
+	}
+	reg.encoders = append(reg.encoders, encoder)
+}
+
+// MediaTypes returns the media types of all registered encoders in preference order
+func (reg *EncoderRegistry) MediaTypes() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	types := make([]string, len(reg.encoders))
+	for i, encoder := range reg.encoders {
+		types[i] = encoder.ContentType()
+	}
+	return types
+}
+
+// Negotiate selects the encoder that best matches an Accept header value.
+// An empty Accept header selects the default encoder; false means nothing acceptable is registered.
+func (reg *EncoderRegistry) Negotiate(accept string) (Encoder, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	if len(reg.encoders) == 0 {
+		return nil, false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return reg.encoders[0], true
+	}
+
+	for _, mediaRange := range parseAccept(accept) {
+		for _, encoder := range reg.encoders {
+			if mediaRange.matches(encoder.ContentType()) {
+				return encoder, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// Negotiated writes data using the encoder negotiated from the request's Accept header.
+// It returns a 406 APIError without writing anything if no registered encoder is acceptable.
+// data is encoded before the status is written, so an encoder error (such as XMLEncoder's 406
+// for maps) is returned with nothing written either.
+func Negotiated[T any](w http.ResponseWriter, r *http.Request, status int, data T, encoders *EncoderRegistry) error {
+	encoder, ok := encoders.Negotiate(r.Header.Get("Accept"))
+	if !ok {
+		return NewAPIError(http.StatusNotAcceptable, "Not Acceptable",
+			"Supported media types: "+strings.Join(encoders.MediaTypes(), ", "))
+	}
+
+	var body bytes.Buffer
+	if err := encoder.Encode(&body, data); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", encoder.ContentType())
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(status)
+	_, err := body.WriteTo(w)
+	return err
+}
+
+// mediaRange is a single parsed entry of an Accept header
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// matches reports whether the media range accepts contentType
+func (m mediaRange) matches(contentType string) bool {
+	if m.mediaType == "*/*" || m.mediaType == contentType {
+		return true
+	}
+	if strings.HasSuffix(m.mediaType, "/*") {
+		return strings.HasPrefix(contentType, strings.TrimSuffix(m.mediaType, "*"))
+	}
+	return false
+}
+
+// parseAccept parses an Accept header into media ranges ordered by quality (highest first).
+// Ranges with q=0 are dropped; malformed entries are ignored.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+	}
+
+	// Stable sort keeps the client's order for equal quality values
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+	return ranges
+}
@@ -2,9 +2,9 @@ package core
 
 import (
 	"fmt"
-	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // HandlerFunc represents a handler that can return an error for cleaner composition
@@ -17,17 +17,36 @@ func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// APIError represents a structured API error
+// APIError represents a structured API error.
+// Code is the HTTP status; ErrorCode is an optional stable machine-readable code (e.g. "user.email_taken").
 type APIError struct {
-	Code    int               `json:"code"`
-	Message string            `json:"message"`
-	Detail  string            `json:"detail,omitempty"`
-	Fields  map[string]string `json:"fields,omitempty"`
+	Code      int               `json:"code"`
+	ErrorCode string            `json:"error_code,omitempty"`
+	Message   string            `json:"message"`
+	Detail    string            `json:"detail,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Errors    []FieldError      `json:"errors,omitempty"`
+
+	// CorrelationID links a server error response to its log entry (the request ID when available)
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// ExposeDetail keeps Detail in 5xx responses in ErrorModeProduction (see WithExposedDetail)
+	ExposeDetail bool `json:"-"`
+
+	// RetryAfter, when positive, is sent as a Retry-After header (in whole seconds)
+	RetryAfter time.Duration `json:"-"`
+
+	// Cause is the underlying error; it is logged but never sent to clients (see Wrap)
+	Cause error `json:"-"`
 }
 
 func (e APIError) Error() string {
 	msg := fmt.Sprintf("API Error %d: %s", e.Code, e.Message)
 
+	if e.ErrorCode != "" {
+		msg += fmt.Sprintf(" (%s)", e.ErrorCode)
+	}
+
 	if e.Detail != "" {
 		msg += fmt.Sprintf(" - %s", e.Detail)
 	}
@@ -40,9 +59,27 @@ func (e APIError) Error() string {
 		msg += fmt.Sprintf(" [fields: %s]", strings.Join(fields, ", "))
 	}
 
+	if e.Cause != nil {
+		msg += fmt.Sprintf(": %s", e.Cause.Error())
+	}
+
 	return msg
 }
 
+// Unwrap returns the underlying cause, so errors.Is and errors.As see through an APIError
+func (e APIError) Unwrap() error {
+	return e.Cause
+}
+
+// FieldError describes one failed validation rule on a request value
+type FieldError struct {
+	Path    string `json:"path"`            // JSON pointer to the value, e.g. "/items/3/email"
+	Field   string `json:"field"`           // Name of the field, e.g. "email"
+	Tag     string `json:"tag"`             // Validation rule that failed, e.g. "required"
+	Param   string `json:"param,omitempty"` // Rule parameter, e.g. "8" for min=8
+	Message string `json:"message"`
+}
+
 // NewAPIError creates a new API error
 func NewAPIError(code int, message string, detail ...string) *APIError {
 	err := &APIError{
@@ -55,6 +92,21 @@ func NewAPIError(code int, message string, detail ...string) *APIError {
 	return err
 }
 
+// Wrap creates an API error caused by err. The response shows only code, message and detail;
+// err is kept for logging and for errors.Is/errors.As. err may be nil.
+//
+// Example:
+//
+//	user, err := loadUser(ctx, id)
+//	if err != nil {
+//	    return nil, core.Wrap(err, http.StatusServiceUnavailable, "User directory unavailable")
+//	}
+func Wrap(err error, code int, message string, detail ...string) *APIError {
+	apiErr := NewAPIError(code, message, detail...)
+	apiErr.Cause = err
+	return apiErr
+}
+
 // NewValidationError creates a new validation error with field details
 func NewValidationError(message string) *APIError {
 	return &APIError{
@@ -80,6 +132,47 @@ func (e *APIError) AddField(fieldName, fieldError string) *APIError {
 	return e
 }
 
+// AddFieldError records a structured validation error and returns the error for chaining.
+// The message is also added to Fields under key, keeping the flat rendering for older clients.
+func (e *APIError) AddFieldError(key string, fieldError FieldError) *APIError {
+	e.Errors = append(e.Errors, fieldError)
+	return e.AddField(key, fieldError.Message)
+}
+
+// WithCode returns a copy of the error carrying the given machine-readable error code.
+// It copies rather than mutates so shared errors such as ErrNotFound stay untouched.
+func (e *APIError) WithCode(errorCode string) *APIError {
+	clone := *e
+	clone.ErrorCode = errorCode
+	if e.Fields != nil {
+		clone.Fields = make(map[string]string, len(e.Fields))
+		for k, v := range e.Fields {
+			clone.Fields[k] = v
+		}
+	}
+	if e.Errors != nil {
+		clone.Errors = append([]FieldError(nil), e.Errors...)
+	}
+	return &clone
+}
+
+// WithCause returns a copy of the error wrapping err, e.g. to attach a cause to a shared
+// error such as ErrNotFound
+func (e *APIError) WithCause(err error) *APIError {
+	clone := e.WithCode(e.ErrorCode)
+	clone.Cause = err
+	return clone
+}
+
+// WithExposedDetail returns a copy of the error carrying a detail that is safe to show clients.
+// Details of 5xx errors are otherwise removed from responses in ErrorModeProduction.
+func (e *APIError) WithExposedDetail(detail string) *APIError {
+	clone := e.WithCode(e.ErrorCode)
+	clone.Detail = detail
+	clone.ExposeDetail = true
+	return clone
+}
+
 // Common API errors
 var (
 	ErrBadRequest   = &APIError{Code: http.StatusBadRequest, Message: "Bad Request"}
@@ -91,22 +184,7 @@ var (
 
 // handleError handles errors in a centralized way
 func handleError(w http.ResponseWriter, r *http.Request, err error) {
-	// Handle APIError types directly
-	if apiErr, ok := err.(*APIError); ok {
-		WriteAPIError(w, r, *apiErr)
-		return
-	}
-
-	// Handle unknown errors - convert to APIError
-	slog.Error("Unexpected error in handler",
-		"original_error", err.Error(),
-		"method", r.Method,
-		"path", r.URL.Path,
-	)
-
-	// Convert to APIError and send response
-	apiErr := NewAPIError(http.StatusInternalServerError, "Internal Server Error", err.Error())
-	WriteAPIError(w, r, *apiErr)
+	WriteError(w, r, err)
 }
 
 // WrapHandler converts a regular http.HandlerFunc to our HandlerFunc
@@ -0,0 +1,263 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Message keys used by the built-in validation messages.
+// Templates may use the {field}, {param} and {tag} placeholders.
+const (
+	MessageValidationFailed       = "validation.failed"        // APIError message for invalid request bodies
+	MessageParamValidationFailed  = "validation.params_failed" // APIError message for invalid path/query parameters
+	MessageValidationFieldDefault = "validation.default"       // Fallback for tags without a template
+)
+
+// MessageCatalog holds localised message templates keyed by locale and message key.
+//
+// Validator field errors are looked up as "validation.<tag>" (with a "validation.<tag>.string"
+// variant for string fields, e.g. min/max lengths). APIErrors carrying an ErrorCode are looked
+// up by that code, so catalog entries replace the message of coded errors.
+type MessageCatalog struct {
+	fallback string
+	messages map[string]map[string]string
+	locales  []string // Registered locales, sorted so negotiation is deterministic
+	mu       sync.RWMutex
+}
+
+// DefaultMessages is the catalog used when no explicit catalog is configured.
+// It ships English validation messages; register further locales with RegisterMessages.
+var DefaultMessages = newDefaultMessages()
+
+// NewMessageCatalog creates an empty catalog. fallbackLocale is used when the client
+// accepts none of the registered locales, and for keys missing from the selected locale.
+// Keys missing from both are rendered from DefaultMessages, so a catalog only needs the
+// messages it adds or overrides.
+func NewMessageCatalog(fallbackLocale string) *MessageCatalog {
+	return &MessageCatalog{
+		fallback: normalizeLocale(fallbackLocale),
+		messages: make(map[string]map[string]string),
+	}
+}
+
+// Register adds or replaces message templates for a locale (e.g. "de" or "pt-BR")
+// and returns the catalog for chaining.
+//
+// Example:
+//
+//	core.DefaultMessages.Register("de", map[string]string{
+//	    "validation.required": "{field} ist erforderlich",
+//	    "user.email_taken":    "Diese E-Mail-Adresse ist bereits registriert",
+//	})
+func (c *MessageCatalog) Register(locale string, messages map[string]string) *MessageCatalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	locale = normalizeLocale(locale)
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string, len(messages))
+		c.locales = append(c.locales, locale)
+		sort.Strings(c.locales)
+	}
+	for key, template := range messages {
+		c.messages[locale][key] = template
+	}
+	return c
+}
+
+// Locales returns the registered locales sorted alphabetically
+func (c *MessageCatalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]string(nil), c.locales...)
+}
+
+// Negotiate selects the registered locale that best matches an Accept-Language header value.
+// An exact tag match wins; otherwise a language-only match ("de-CH" → "de", "de" → "de-DE")
+// is used, preferring the alphabetically first regional variant ("de" → "de-AT" over "de-DE").
+// The fallback locale is returned when nothing matches.
+func (c *MessageCatalog) Negotiate(acceptLanguage string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, lang := range parseAcceptLanguage(acceptLanguage) {
+		if lang == "*" {
+			break
+		}
+		if _, ok := c.messages[lang]; ok {
+			return lang
+		}
+
+		base, _, _ := strings.Cut(lang, "-")
+		if _, ok := c.messages[base]; ok {
+			return base
+		}
+		for _, locale := range c.locales {
+			if strings.HasPrefix(locale, base+"-") {
+				return locale
+			}
+		}
+	}
+
+	return c.fallback
+}
+
+// Localizer returns a Localizer for the given locale
+func (c *MessageCatalog) Localizer(locale string) Localizer {
+	return Localizer{catalog: c, locale: normalizeLocale(locale)}
+}
+
+// lookup returns the first template found for keys in locale
+func (c *MessageCatalog) lookup(locale string, keys []string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	messages := c.messages[locale]
+	for _, key := range keys {
+		if template, ok := messages[key]; ok {
+			return template, true
+		}
+	}
+	return "", false
+}
+
+// RegisterMessages adds message templates for a locale to DefaultMessages
+func RegisterMessages(locale string, messages map[string]string) {
+	DefaultMessages.Register(locale, messages)
+}
+
+// Localizer renders messages from a catalog in one negotiated locale
+type Localizer struct {
+	catalog *MessageCatalog
+	locale  string
+}
+
+// Locale returns the locale messages are rendered in
+func (l Localizer) Locale() string {
+	return l.locale
+}
+
+// Format renders the first template found for keys, trying the localizer's locale before the
+// catalog's fallback locale. Placeholders like {field} are replaced from args.
+// It returns false if no template exists for any key.
+func (l Localizer) Format(args map[string]string, keys ...string) (string, bool) {
+	message, _, ok := l.render(args, keys)
+	return message, ok
+}
+
+// render is Format that also reports the locale the template was found in
+func (l Localizer) render(args map[string]string, keys []string) (string, string, bool) {
+	if l.catalog == nil {
+		return "", "", false
+	}
+
+	locale := l.locale
+	template, ok := l.catalog.lookup(locale, keys)
+	if !ok && locale != l.catalog.fallback {
+		locale = l.catalog.fallback
+		template, ok = l.catalog.lookup(locale, keys)
+	}
+	if !ok && l.catalog != DefaultMessages {
+		// Keys the catalog does not define keep their built-in messages
+		return DefaultMessages.Localizer(l.locale).render(args, keys)
+	}
+	if !ok {
+		return "", "", false
+	}
+
+	if len(args) == 0 {
+		return template, locale, true
+	}
+	replacements := make([]string, 0, len(args)*2)
+	for name, value := range args {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(replacements...).Replace(template), locale, true
+}
+
+// messageCatalogContextKey is the context key holding the request's MessageCatalog
+type messageCatalogContextKey struct{}
+
+// ContextWithMessageCatalog returns a copy of ctx that carries the given catalog
+func ContextWithMessageCatalog(ctx context.Context, catalog *MessageCatalog) context.Context {
+	return context.WithValue(ctx, messageCatalogContextKey{}, catalog)
+}
+
+// MessageCatalogFromContext returns the catalog stored in ctx, or DefaultMessages if none is set
+func MessageCatalogFromContext(ctx context.Context) *MessageCatalog {
+	if catalog, ok := ctx.Value(messageCatalogContextKey{}).(*MessageCatalog); ok && catalog != nil {
+		return catalog
+	}
+	return DefaultMessages
+}
+
+// WithMessageCatalog returns HTTP middleware that selects the message catalog for every request it serves
+func WithMessageCatalog(catalog *MessageCatalog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(ContextWithMessageCatalog(r.Context(), catalog)))
+		})
+	}
+}
+
+// LocalizerFor returns a Localizer for the request's catalog, in the locale negotiated
+// from its Accept-Language header
+func LocalizerFor(r *http.Request) Localizer {
+	catalog := MessageCatalogFromContext(r.Context())
+	return catalog.Localizer(catalog.Negotiate(r.Header.Get("Accept-Language")))
+}
+
+// localizeAPIError replaces the message of a coded error with its catalog entry, if any.
+// It reports the locale used so the response can carry Content-Language.
+func localizeAPIError(r *http.Request, apiErr APIError) (APIError, string) {
+	if apiErr.ErrorCode == "" {
+		return apiErr, ""
+	}
+
+	message, locale, ok := LocalizerFor(r).render(nil, []string{apiErr.ErrorCode})
+	if !ok {
+		return apiErr, ""
+	}
+	apiErr.Message = message
+	return apiErr, locale
+}
+
+// parseAcceptLanguage parses an Accept-Language header into normalized language tags
+// ordered by quality (highest first). Tags with q=0 are dropped.
+func parseAcceptLanguage(acceptLanguage string) []string {
+	// Language ranges share the Accept header syntax (token;q=value)
+	ranges := parseAccept(acceptLanguage)
+
+	tags := make([]string, len(ranges))
+	for i, languageRange := range ranges {
+		tags[i] = normalizeLocale(languageRange.mediaType)
+	}
+	return tags
+}
+
+// normalizeLocale lower-cases a language tag and uses "-" as the subtag separator
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// newDefaultMessages builds DefaultMessages with the built-in English templates
+func newDefaultMessages() *MessageCatalog {
+	return NewMessageCatalog("en").Register("en", map[string]string{
+		MessageValidationFailed:       "Validation failed",
+		MessageParamValidationFailed:  "Parameter validation failed",
+		MessageValidationFieldDefault: "{field} validation failed on '{tag}' tag",
+		"validation.required":         "{field} is required",
+		"validation.min":              "{field} must be at least {param}",
+		"validation.min.string":       "{field} must be at least {param} characters",
+		"validation.max":              "{field} must be at most {param}",
+		"validation.max.string":       "{field} must be at most {param} characters",
+		"validation.email":            "{field} must be a valid email address",
+		"validation.uuid":             "{field} must be a valid UUID",
+		"validation.url":              "{field} must be a valid URL",
+		"validation.eqfield":          "{field} must match {param}",
+	})
+}
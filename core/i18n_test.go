@@ -0,0 +1,35 @@
+package core
+
+import "testing"
+
+// TestMessageCatalogNegotiate verifies locale negotiation is exact, then by language, then deterministic across regions
+func TestMessageCatalogNegotiate(t *testing.T) {
+	catalog := NewMessageCatalog("en").
+		Register("en", map[string]string{"greeting": "Hello"}).
+		Register("de-DE", map[string]string{"greeting": "Hallo"}).
+		Register("de-AT", map[string]string{"greeting": "Servus"}).
+		Register("fr", map[string]string{"greeting": "Bonjour"})
+
+	tests := map[string]string{
+		"de-DE":           "de-de",
+		"fr-CA":           "fr",
+		"es, fr;q=0.5":    "fr",
+		"pt":              "en",
+		"":                "en",
+		"de":              "de-at",
+		"de-CH, en;q=0.8": "de-at",
+		"it, *;q=0.1":     "en",
+	}
+	for acceptLanguage, expected := range tests {
+		// Repeat to catch map iteration order leaking into the result
+		for range 20 {
+			if got := catalog.Negotiate(acceptLanguage); got != expected {
+				t.Fatalf("Negotiate(%q) = %q, expected %q", acceptLanguage, got, expected)
+			}
+		}
+	}
+
+	if locales := catalog.Locales(); len(locales) != 4 || locales[0] != "de-at" || locales[3] != "fr" {
+		t.Errorf("Expected sorted locales, got %v", locales)
+	}
+}
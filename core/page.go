@@ -0,0 +1,251 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Page size limits applied by PageParams
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// PageParams holds the standard pagination query parameters.
+// Embed it in a handler's params struct; typed.ParseParams fills it from the query string.
+//
+// Offset pagination uses limit/offset. Cursor pagination uses limit/cursor, where cursor is
+// an opaque value produced by a CursorCodec. When a cursor is present, offset is ignored.
+// Limits above MaxPageLimit are not rejected; read the limit with PageLimit, which caps them.
+//
+// Example:
+//
+//	type ListUsersParams struct {
+//	    core.PageParams
+//	    Status string `query:"status"`
+//	}
+type PageParams struct {
+	Limit  int    `query:"limit" validate:"omitempty,min=1" description:"Maximum number of items to return (default 20; larger values are capped at 100)"`
+	Offset int    `query:"offset" validate:"omitempty,min=0" description:"Number of items to skip (offset pagination)"`
+	Cursor string `query:"cursor" description:"Opaque cursor from a previous page link (cursor pagination)"`
+}
+
+// PageLimit returns the requested limit, or DefaultPageLimit if none was given, capped at MaxPageLimit
+func (p PageParams) PageLimit() int {
+	if p.Limit <= 0 {
+		return DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return p.Limit
+}
+
+// Page is a paginated list response.
+// Build it with OffsetPage or CursorPage and write it with WritePage (or typed.ResponsePage),
+// which fills Links and the RFC 8288 Link header from the request URL.
+type Page[T any] struct {
+	Data  []T       `json:"data"`
+	Count int       `json:"count"`           // Number of items in this page
+	Total *int      `json:"total,omitempty"` // Total number of items (offset pagination only)
+	Limit int       `json:"limit"`
+	Links PageLinks `json:"links"`
+
+	// Query parameter changes producing each link; an empty value removes the parameter
+	linkQueries map[string]url.Values
+}
+
+// PageLinks holds the URLs of neighbouring pages, relative to the API host
+type PageLinks struct {
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// OffsetPage builds a page for limit/offset pagination.
+// total is the number of items across all pages (e.g. from SELECT COUNT(*)).
+func OffsetPage[T any](data []T, params PageParams, total int) Page[T] {
+	limit := params.PageLimit()
+	offset := max(params.Offset, 0)
+
+	page := newPage(data, limit)
+	page.Total = &total
+
+	link := func(rel string, offset int) {
+		page.linkQueries[rel] = url.Values{
+			"limit":  {strconv.Itoa(limit)},
+			"offset": {strconv.Itoa(offset)},
+			"cursor": {""},
+		}
+	}
+
+	link("first", 0)
+	if offset > 0 {
+		link("prev", max(offset-limit, 0))
+	}
+	if offset+len(data) < total {
+		link("next", offset+limit)
+	}
+	if total > 0 {
+		link("last", ((total-1)/limit)*limit)
+	}
+
+	return page
+}
+
+// CursorPage builds a page for cursor (keyset) pagination.
+// nextCursor and prevCursor are opaque cursors from a CursorCodec; pass "" when there is no such page.
+//
+// Example:
+//
+//	users, _ := fetchUsersAfter(ctx, after, params.PageLimit()+1) // one extra row detects a next page
+//	next := ""
+//	if len(users) > params.PageLimit() {
+//	    users = users[:params.PageLimit()]
+//	    next, _ = cursors.Encode(UserCursor{ID: users[len(users)-1].ID})
+//	}
+//	return core.CursorPage(users, params.PageParams, next, ""), nil
+func CursorPage[T any](data []T, params PageParams, nextCursor, prevCursor string) Page[T] {
+	limit := params.PageLimit()
+	page := newPage(data, limit)
+
+	link := func(rel, cursor string) {
+		page.linkQueries[rel] = url.Values{
+			"limit":  {strconv.Itoa(limit)},
+			"cursor": {cursor},
+			"offset": {""},
+		}
+	}
+
+	if prevCursor != "" {
+		link("prev", prevCursor)
+	}
+	if nextCursor != "" {
+		link("next", nextCursor)
+	}
+
+	return page
+}
+
+// newPage creates a page with no links; nil data is written as an empty array
+func newPage[T any](data []T, limit int) Page[T] {
+	if data == nil {
+		data = []T{}
+	}
+	return Page[T]{
+		Data:        data,
+		Count:       len(data),
+		Limit:       limit,
+		linkQueries: make(map[string]url.Values),
+	}
+}
+
+// WritePage resolves the page links against the request URL, adds them as an RFC 8288 Link header,
+// and writes the page as JSON. Query parameters other than limit/offset/cursor are preserved.
+func WritePage[T any](w http.ResponseWriter, r *http.Request, status int, page Page[T]) error {
+	links := make([]string, 0, len(page.linkQueries))
+	for _, rel := range []string{"first", "prev", "next", "last"} {
+		changes, ok := page.linkQueries[rel]
+		if !ok {
+			continue
+		}
+
+		link := pageURL(r, changes)
+		switch rel {
+		case "first":
+			page.Links.First = link
+		case "prev":
+			page.Links.Prev = link
+		case "next":
+			page.Links.Next = link
+		case "last":
+			page.Links.Last = link
+		}
+		links = append(links, "<"+link+`>; rel="`+rel+`"`)
+	}
+
+	if len(links) > 0 {
+		// Add keeps links set earlier, e.g. the successor-version link of a deprecated route
+		w.Header().Add("Link", strings.Join(links, ", "))
+	}
+	return JSON(w, status, page)
+}
+
+// pageURL applies query changes to the request URL and returns it as a host-relative reference
+func pageURL(r *http.Request, changes url.Values) string {
+	query := r.URL.Query()
+	for key, values := range changes {
+		if len(values) == 0 || values[0] == "" {
+			query.Del(key)
+			continue
+		}
+		query[key] = values
+	}
+
+	link := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return link.String()
+}
+
+// CursorCodec produces tamper-proof opaque cursors for keyset pagination.
+// A cursor is the JSON encoding of a position value (e.g. the last row's sort key and ID),
+// signed with HMAC-SHA256 so clients cannot forge positions.
+type CursorCodec struct {
+	key []byte
+}
+
+// NewCursorCodec creates a codec signing cursors with secret.
+// Use at least 32 random bytes and keep the secret stable across instances, or cursors
+// issued by one instance will be rejected by another. Panics if secret is empty.
+func NewCursorCodec(secret []byte) *CursorCodec {
+	if len(secret) == 0 {
+		panic("core: cursor secret must not be empty")
+	}
+	return &CursorCodec{key: append([]byte(nil), secret...)}
+}
+
+// Encode signs position and returns it as a URL-safe cursor
+func (c *CursorCodec) Encode(position any) (string, error) {
+	payload, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode verifies cursor and unmarshals its position into v.
+// A malformed or forged cursor yields a 400 APIError with error code "pagination.invalid_cursor".
+func (c *CursorCodec) Decode(cursor string, v any) error {
+	invalid := NewAPIError(http.StatusBadRequest, "Invalid cursor").WithCode("pagination.invalid_cursor")
+
+	encodedPayload, encodedSignature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return invalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return invalid
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return invalid
+	}
+	return nil
+}
+
+// sign computes the HMAC-SHA256 of payload
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
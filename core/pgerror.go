@@ -0,0 +1,153 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL SQLSTATE codes translated by PgErrorTranslator
+const (
+	PgUniqueViolation      = "23505"
+	PgForeignKeyViolation  = "23503"
+	PgNotNullViolation     = "23502"
+	PgCheckViolation       = "23514"
+	PgSerializationFailure = "40001"
+	PgDeadlockDetected     = "40P01"
+	PgQueryCanceled        = "57014"
+)
+
+// ConstraintMapping overrides the generic translation for a named constraint
+type ConstraintMapping struct {
+	Field     string // Field name reported in APIError.Fields
+	Message   string // Field error message (also used as the APIError message if set)
+	Status    int    // Optional: overrides the status derived from the SQLSTATE
+	ErrorCode string // Optional: overrides the machine-readable error code
+}
+
+// PgErrorTranslator maps PostgreSQL errors to APIErrors by SQLSTATE, with per-constraint overrides.
+// The raw PostgreSQL message is never copied into the response.
+type PgErrorTranslator struct {
+	constraints      map[string]ConstraintMapping
+	foreignKeyStatus int
+	retryAfter       time.Duration
+	mu               sync.RWMutex
+}
+
+// NewPgErrorTranslator creates a translator with default mappings:
+//   - 23505 unique violation → 409
+//   - 23503 foreign key violation → 409 (see WithForeignKeyStatus)
+//   - 23502 not null / 23514 check violation → 422
+//   - 40001 serialization failure / 40P01 deadlock → 503 with Retry-After
+//   - 57014 query canceled → 504
+//   - any other PostgreSQL error → 500 with a generic message
+func NewPgErrorTranslator() *PgErrorTranslator {
+	return &PgErrorTranslator{
+		constraints:      make(map[string]ConstraintMapping),
+		foreignKeyStatus: http.StatusConflict,
+		retryAfter:       time.Second,
+	}
+}
+
+// WithForeignKeyStatus sets the status for foreign key violations (typically 409 or 422)
+func (t *PgErrorTranslator) WithForeignKeyStatus(status int) *PgErrorTranslator {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.foreignKeyStatus = status
+	return t
+}
+
+// WithRetryAfter sets the Retry-After hint for serialization failures and deadlocks
+func (t *PgErrorTranslator) WithRetryAfter(retryAfter time.Duration) *PgErrorTranslator {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.retryAfter = retryAfter
+	return t
+}
+
+// MapConstraint registers an override for a constraint name and returns the translator for chaining.
+//
+// Example:
+//
+//	translator := core.NewPgErrorTranslator().
+//	    MapConstraint("users_email_key", core.ConstraintMapping{
+//	        Field:     "email",
+//	        Message:   "email is already registered",
+//	        ErrorCode: "user.email_taken",
+//	    })
+func (t *PgErrorTranslator) MapConstraint(constraintName string, mapping ConstraintMapping) *PgErrorTranslator {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.constraints[constraintName] = mapping
+	return t
+}
+
+// Translate converts err to an APIError if it wraps a *pgconn.PgError.
+// It returns false for errors that did not originate from PostgreSQL.
+func (t *PgErrorTranslator) Translate(err error) (*APIError, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil, false
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	apiErr := t.translateCode(pgErr)
+
+	// Apply per-constraint overrides
+	if mapping, ok := t.constraints[pgErr.ConstraintName]; ok && pgErr.ConstraintName != "" {
+		if mapping.Status != 0 {
+			apiErr.Code = mapping.Status
+		}
+		if mapping.ErrorCode != "" {
+			apiErr.ErrorCode = mapping.ErrorCode
+		}
+		if mapping.Message != "" {
+			apiErr.Message = mapping.Message
+		}
+		if mapping.Field != "" {
+			apiErr.AddField(mapping.Field, mapping.Message)
+		}
+	}
+
+	return apiErr, true
+}
+
+// translateCode maps the SQLSTATE to a generic APIError
+func (t *PgErrorTranslator) translateCode(pgErr *pgconn.PgError) *APIError {
+	switch pgErr.Code {
+	case PgUniqueViolation:
+		return NewAPIError(http.StatusConflict, "Resource already exists").WithCode("db.unique_violation")
+	case PgForeignKeyViolation:
+		return NewAPIError(t.foreignKeyStatus, "Referenced resource does not exist or is still in use").WithCode("db.foreign_key_violation")
+	case PgNotNullViolation:
+		apiErr := NewAPIError(http.StatusUnprocessableEntity, "Required value is missing").WithCode("db.not_null_violation")
+		if pgErr.ColumnName != "" {
+			apiErr.AddField(pgErr.ColumnName, pgErr.ColumnName+" is required")
+		}
+		return apiErr
+	case PgCheckViolation:
+		return NewAPIError(http.StatusUnprocessableEntity, "Value violates a constraint").WithCode("db.check_violation")
+	case PgSerializationFailure, PgDeadlockDetected:
+		apiErr := NewAPIError(http.StatusServiceUnavailable, "Concurrent update conflict, please retry").WithCode("db.retryable")
+		apiErr.RetryAfter = t.retryAfter
+		return apiErr
+	case PgQueryCanceled:
+		return NewAPIError(http.StatusGatewayTimeout, "Database query timed out").WithCode("db.query_canceled")
+	}
+
+	// Remaining integrity constraint violations (class 23)
+	if strings.HasPrefix(pgErr.Code, "23") {
+		return NewAPIError(http.StatusConflict, "Integrity constraint violation").WithCode("db.integrity_violation")
+	}
+
+	return NewAPIError(StatusDatabaseError, "Database error").WithCode("db.error")
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ErrorFormat selects the wire format used by WriteAPIError
+type ErrorFormat string
+
+const (
+	// ErrorFormatEnvelope writes errors as {"error": APIError} (the default)
+	ErrorFormatEnvelope ErrorFormat = "envelope"
+
+	// ErrorFormatProblem writes errors as RFC 9457 application/problem+json
+	ErrorFormatProblem ErrorFormat = "problem"
+)
+
+// ProblemContentType is the media type for RFC 9457 problem details
+const ProblemContentType = "application/problem+json"
+
+// requestIDContextKey mirrors middleware/http.RequestIDContextKey so core can
+// read the request ID without depending on the middleware layer
+const requestIDContextKey = "request_id"
+
+// errorFormatContextKey is the context key holding the selected ErrorFormat
+type errorFormatContextKey struct{}
+
+// ContextWithErrorFormat returns a copy of ctx that carries the given error format.
+// WriteAPIError reads it back to decide how to render error responses.
+func ContextWithErrorFormat(ctx context.Context, format ErrorFormat) context.Context {
+	return context.WithValue(ctx, errorFormatContextKey{}, format)
+}
+
+// ErrorFormatFromContext returns the error format stored in ctx, or ErrorFormatEnvelope if none is set
+func ErrorFormatFromContext(ctx context.Context) ErrorFormat {
+	if format, ok := ctx.Value(errorFormatContextKey{}).(ErrorFormat); ok && format != "" {
+		return format
+	}
+	return ErrorFormatEnvelope
+}
+
+// WithErrorFormat returns HTTP middleware that selects the error format for every request it serves
+func WithErrorFormat(format ErrorFormat) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(ContextWithErrorFormat(r.Context(), format)))
+		})
+	}
+}
+
+// ProblemDetails is an RFC 9457 problem details object.
+// Extensions are serialized as top-level members alongside the standard ones.
+type ProblemDetails struct {
+	Type       string         `json:"type"`
+	Title      string         `json:"title"`
+	Status     int            `json:"status"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Extensions map[string]any `json:"-"`
+}
+
+// MarshalJSON flattens Extensions into the top-level object.
+// Standard members always win over extensions with the same name.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	out := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		out[k] = v
+	}
+
+	out["type"] = p.Type
+	out["title"] = p.Title
+	out["status"] = p.Status
+	if p.Detail != "" {
+		out["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		out["instance"] = p.Instance
+	}
+
+	return json.Marshal(out)
+}
+
+// NewProblemDetails converts an APIError into RFC 9457 problem details.
+// The request ID (if any) becomes the instance; the error code, validation fields, structured
+// validation errors and correlation ID are exposed as the "code", "fields", "errors" and
+// "correlation_id" extensions.
+func NewProblemDetails(r *http.Request, apiErr APIError) ProblemDetails {
+	problem := ProblemDetails{
+		Type:     "about:blank",
+		Title:    apiErr.Message,
+		Status:   apiErr.Code,
+		Detail:   apiErr.Detail,
+		Instance: requestIDFromRequest(r),
+	}
+
+	if apiErr.ErrorCode != "" || len(apiErr.Fields) > 0 || len(apiErr.Errors) > 0 || apiErr.CorrelationID != "" {
+		problem.Extensions = make(map[string]any)
+	}
+	if apiErr.ErrorCode != "" {
+		problem.Extensions["code"] = apiErr.ErrorCode
+	}
+	if len(apiErr.Fields) > 0 {
+		problem.Extensions["fields"] = apiErr.Fields
+	}
+	if len(apiErr.Errors) > 0 {
+		problem.Extensions["errors"] = apiErr.Errors
+	}
+	if apiErr.CorrelationID != "" {
+		problem.Extensions["correlation_id"] = apiErr.CorrelationID
+	}
+
+	return problem
+}
+
+// writeProblem sends an APIError as an application/problem+json response
+func writeProblem(w http.ResponseWriter, r *http.Request, apiErr APIError) error {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(apiErr.Code)
+	return json.NewEncoder(w).Encode(NewProblemDetails(r, apiErr))
+}
+
+// requestIDFromRequest returns the request ID set by chi's RequestID middleware,
+// japi-core's http.WithRequestID middleware, or the X-Request-ID header, in that order
+func requestIDFromRequest(r *http.Request) string {
+	if requestID := middleware.GetReqID(r.Context()); requestID != "" {
+		return requestID
+	}
+	if requestID, ok := r.Context().Value(requestIDContextKey).(string); ok && requestID != "" {
+		return requestID
+	}
+	return r.Header.Get("X-Request-ID")
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PanicObserver is notified of every panic recovered while serving a request
+// (e.g. metrics.Collector counts them)
+type PanicObserver interface {
+	ObservePanic(r *http.Request)
+}
+
+// recoveryScope is the mutable, request-scoped state read when a panic is recovered.
+// Middleware and handlers further down the chain fill it in, since context values
+// set below the recovery point are not visible to it.
+type recoveryScope struct {
+	mu        sync.Mutex
+	user      string
+	observers []PanicObserver
+}
+
+// recoveryScopeContextKey is the context key holding the request's *recoveryScope
+type recoveryScopeContextKey struct{}
+
+// withRecoveryScope returns r with a recovery scope, reusing one installed further up the chain
+func withRecoveryScope(r *http.Request) (*http.Request, *recoveryScope) {
+	if scope, ok := r.Context().Value(recoveryScopeContextKey{}).(*recoveryScope); ok {
+		return r, scope
+	}
+	scope := &recoveryScope{}
+	return r.WithContext(context.WithValue(r.Context(), recoveryScopeContextKey{}, scope)), scope
+}
+
+// WithPanicObserver returns r registered to notify observer of recovered panics
+func WithPanicObserver(r *http.Request, observer PanicObserver) *http.Request {
+	r, scope := withRecoveryScope(r)
+	scope.mu.Lock()
+	scope.observers = append(scope.observers, observer)
+	scope.mu.Unlock()
+	return r
+}
+
+// SetRequestUser records the authenticated user for panic logs of the request carrying ctx.
+// It has no effect unless Recoverer, ServeRecovered (used by typed handlers) or the metrics
+// middleware serves the request.
+func SetRequestUser(ctx context.Context, user string) {
+	if scope, ok := ctx.Value(recoveryScopeContextKey{}).(*recoveryScope); ok {
+		scope.mu.Lock()
+		scope.user = user
+		scope.mu.Unlock()
+	}
+}
+
+// Recoverer is HTTP middleware that turns panics into a 500 APIError response in the
+// request's ErrorFormat and ErrorMode, instead of chi's plain-text response.
+// The panic value and stack are logged with the request ID (as correlation_id), route pattern and user.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeRecovered(w, r, next.ServeHTTP)
+	})
+}
+
+// ServeRecovered calls serve, recovering any panic as Recoverer does. It installs the
+// recovery scope read by SetRequestUser unless Recoverer or the metrics middleware already did,
+// so panics of handlers mounted on any router are logged with the user.
+// http.ErrAbortHandler is re-panicked so net/http can abort the response.
+func ServeRecovered(w http.ResponseWriter, r *http.Request, serve http.HandlerFunc) {
+	r, _ = withRecoveryScope(r)
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		if recovered == http.ErrAbortHandler {
+			panic(recovered)
+		}
+		writePanic(w, r, recovered)
+	}()
+
+	serve(w, r)
+}
+
+// writePanic logs a recovered panic, notifies observers and writes the 500 response
+func writePanic(w http.ResponseWriter, r *http.Request, recovered any) {
+	var user string
+	var observers []PanicObserver
+	if scope, ok := r.Context().Value(recoveryScopeContextKey{}).(*recoveryScope); ok {
+		scope.mu.Lock()
+		user = scope.user
+		observers = append(observers, scope.observers...)
+		scope.mu.Unlock()
+	}
+
+	apiErr := APIError{
+		Code:          http.StatusInternalServerError,
+		Message:       "Internal Server Error",
+		CorrelationID: requestIDFromRequest(r),
+	}
+	if ErrorModeFromContext(r.Context()) == ErrorModeDevelopment {
+		apiErr.Detail = fmt.Sprintf("panic: %v", recovered)
+	}
+
+	logFields := []any{
+		"panic", fmt.Sprint(recovered),
+		"stack", string(debug.Stack()),
+		"route", routePattern(r),
+	}
+	if user != "" {
+		logFields = append(logFields, "user", user)
+	}
+	if apiErr.CorrelationID != "" {
+		logFields = append(logFields, "correlation_id", apiErr.CorrelationID)
+	}
+	logFields = append(logFields, extractRequestContext(r)...)
+	slog.Error("Panic recovered", logFields...)
+
+	for _, observer := range observers {
+		observer.ObservePanic(r)
+	}
+
+	// A protocol switch has no HTTP response left to write to
+	if r.Header.Get("Connection") != "Upgrade" {
+		WriteAPIError(w, r, apiErr)
+	}
+}
+
+// routePattern returns the chi route pattern matched so far, or the request path
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		return rctx.RoutePattern()
+	}
+	return r.URL.Path
+}
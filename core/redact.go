@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ErrorMode controls how much of an unexpected error reaches the client
+type ErrorMode string
+
+const (
+	// ErrorModeProduction replaces unexpected errors and the detail of 5xx APIErrors with a
+	// generic message and a correlation ID; the full error chain is only logged (the default)
+	ErrorModeProduction ErrorMode = "production"
+
+	// ErrorModeDevelopment sends the underlying error text to the client as the detail
+	ErrorModeDevelopment ErrorMode = "development"
+)
+
+// errorModeContextKey is the context key holding the selected ErrorMode
+type errorModeContextKey struct{}
+
+// ContextWithErrorMode returns a copy of ctx that carries the given error mode
+func ContextWithErrorMode(ctx context.Context, mode ErrorMode) context.Context {
+	return context.WithValue(ctx, errorModeContextKey{}, mode)
+}
+
+// ErrorModeFromContext returns the error mode stored in ctx, or ErrorModeProduction if none is set
+func ErrorModeFromContext(ctx context.Context) ErrorMode {
+	if mode, ok := ctx.Value(errorModeContextKey{}).(ErrorMode); ok && mode != "" {
+		return mode
+	}
+	return ErrorModeProduction
+}
+
+// WithErrorMode returns HTTP middleware that selects the error mode for every request it serves
+func WithErrorMode(mode ErrorMode) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(ContextWithErrorMode(r.Context(), mode)))
+		})
+	}
+}
+
+// WriteError writes any handler error as an API error response.
+// An APIError anywhere in err's chain is written with its own status (see AsAPIError) and
+// err is logged as its cause; anything else is converted with InternalError.
+// It is the shared error path of core.HandlerFunc, router.AdaptErrorHandler and typed handlers.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) error {
+	if apiErr, ok := AsAPIError(err); ok {
+		cause := apiErr.Cause
+		if error(apiErr) != err {
+			cause = err // keep the wrapping context, e.g. "load user: API Error 404: ..."
+		}
+		return writeAPIError(w, r, *apiErr, cause)
+	}
+	return WriteAPIError(w, r, InternalError(r, err))
+}
+
+// AsAPIError finds the first APIError in err's chain, so an APIError wrapped with
+// fmt.Errorf("...: %w", apiErr) keeps its status
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	var value APIError
+	if errors.As(err, &value) {
+		return &value, true
+	}
+	return nil, false
+}
+
+// InternalError converts an unexpected error into a 500 APIError and logs its full chain.
+// In ErrorModeProduction the response carries only a generic message and a correlation ID
+// (the request ID when there is one) that matches the log entry; in ErrorModeDevelopment
+// the error text is kept as the detail.
+func InternalError(r *http.Request, err error) APIError {
+	correlationID := requestIDFromRequest(r)
+	if correlationID == "" {
+		correlationID = uuid.NewString()
+	}
+
+	logFields := []any{
+		"error", err.Error(),
+		"error_chain", errorChain(err),
+		"correlation_id", correlationID,
+	}
+	slog.Error("Unexpected error in handler", append(logFields, extractRequestContext(r)...)...)
+
+	apiErr := APIError{
+		Code:          http.StatusInternalServerError,
+		Message:       "Internal Server Error",
+		CorrelationID: correlationID,
+	}
+	if ErrorModeFromContext(r.Context()) == ErrorModeDevelopment {
+		apiErr.Detail = err.Error()
+	}
+	return apiErr
+}
+
+// redactAPIError strips the detail of a 5xx APIError in production mode unless the error
+// opted in via WithExposedDetail, and attaches a correlation ID to server errors
+func redactAPIError(r *http.Request, apiErr APIError) APIError {
+	if apiErr.Code < 500 {
+		return apiErr
+	}
+
+	if apiErr.CorrelationID == "" {
+		apiErr.CorrelationID = requestIDFromRequest(r)
+	}
+	if !apiErr.ExposeDetail && ErrorModeFromContext(r.Context()) == ErrorModeProduction {
+		apiErr.Detail = ""
+	}
+	return apiErr
+}
+
+// errorChain describes each error in err's chain (including errors.Join branches) by type and message
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(error)
+	walk = func(err error) {
+		for err != nil {
+			chain = append(chain, fmt.Sprintf("%T: %s", err, err.Error()))
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, branch := range joined.Unwrap() {
+					walk(branch)
+				}
+				return
+			}
+			err = errors.Unwrap(err)
+		}
+	}
+	walk(err)
+	return chain
+}
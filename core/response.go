@@ -3,7 +3,9 @@ package core
 import (
 	"encoding/json"
 	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 // Simple response helper functions
@@ -49,7 +51,8 @@ func List[T any](w http.ResponseWriter, data []T) error {
 	return JSON(w, http.StatusOK, response)
 }
 
-// Health sends a health check response
+// Health sends a health check response.
+// For liveness/readiness probes backed by checkers, use the health package.
 func Health(w http.ResponseWriter, status string, checks map[string]bool) error {
 	response := map[string]any{
 		"status": status,
@@ -64,18 +67,69 @@ func Error(w http.ResponseWriter, r *http.Request, status int, message string) e
 	return WriteAPIError(w, r, *apiErr)
 }
 
-// WriteAPIError sends an error response for APIError types with comprehensive logging
+// WriteAPIError sends an error response for APIError types with comprehensive logging.
+// The response body uses the ErrorFormat carried by the request context (see WithErrorFormat).
+// In ErrorModeProduction the detail of 5xx errors is logged but not sent (see WithErrorMode).
 func WriteAPIError(w http.ResponseWriter, r *http.Request, apiErr APIError) error {
+	return writeAPIError(w, r, apiErr, apiErr.Cause)
+}
+
+// writeAPIError implements WriteAPIError, logging cause as the underlying error
+func writeAPIError(w http.ResponseWriter, r *http.Request, apiErr APIError, cause error) error {
+	// Redact server error details for the client; the log keeps them
+	redacted := redactAPIError(r, apiErr)
+	logAPIError(r, apiErr, redacted, cause)
+	apiErr = redacted
+
+	// Replace the message of coded errors with the client's language, if the catalog has it
+	if localized, locale := localizeAPIError(r, apiErr); locale != "" {
+		apiErr = localized
+		w.Header().Set("Content-Language", locale)
+		w.Header().Add("Vary", "Accept-Language")
+	}
+
+	// Tell clients when a retryable error may be retried
+	if apiErr.RetryAfter > 0 {
+		seconds := int(math.Ceil(apiErr.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+
+	// RFC 9457 problem details when selected for this request
+	if ErrorFormatFromContext(r.Context()) == ErrorFormatProblem {
+		return writeProblem(w, r, apiErr)
+	}
+
+	// Unified response structure
+	response := map[string]any{
+		"error": apiErr,
+	}
+	return JSON(w, apiErr.Code, response)
+}
+
+// logAPIError logs an error response before redaction, with the correlation ID the client receives
+func logAPIError(r *http.Request, apiErr APIError, redacted APIError, cause error) {
 	// Build log fields
 	logFields := []any{
 		"status", apiErr.Code,
 		"message", apiErr.Message,
 	}
 
+	if apiErr.ErrorCode != "" {
+		logFields = append(logFields, "error_code", apiErr.ErrorCode)
+	}
+
 	if apiErr.Detail != "" {
 		logFields = append(logFields, "detail", apiErr.Detail)
 	}
 
+	if cause != nil {
+		logFields = append(logFields, "cause", cause.Error())
+	}
+
+	if redacted.CorrelationID != "" {
+		logFields = append(logFields, "correlation_id", redacted.CorrelationID)
+	}
+
 	if len(apiErr.Fields) > 0 {
 		logFields = append(logFields, "validation_field_count", len(apiErr.Fields))
 		logFields = append(logFields, "validation_fields", apiErr.Fields)
@@ -92,12 +146,6 @@ func WriteAPIError(w http.ResponseWriter, r *http.Request, apiErr APIError) erro
 	} else {
 		slog.Info("API error response", logFields...)
 	}
-
-	// Unified response structure
-	response := map[string]any{
-		"error": apiErr,
-	}
-	return JSON(w, apiErr.Code, response)
 }
 
 // extractRequestContext extracts useful request context for logging
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventStreamContentType is the media type for Server-Sent Events
+const EventStreamContentType = "text/event-stream"
+
+// SSEEvent is a single Server-Sent Event. Data is JSON-encoded into the event's data field.
+type SSEEvent[T any] struct {
+	ID    string        // Optional: event ID, echoed back by clients as Last-Event-ID on reconnect
+	Event string        // Optional: event name (defaults to "message" on the client)
+	Data  T             // Event payload
+	Retry time.Duration // Optional: client reconnection delay
+}
+
+// SSEStream produces events for one client connection.
+// It should emit events until ctx is done or it has nothing more to send.
+// lastEventID is the Last-Event-ID sent by a reconnecting client (empty on first connect).
+type SSEStream[T any] func(ctx context.Context, lastEventID string, emit func(SSEEvent[T]) error) error
+
+// SSEOptions configures Server-Sent Events delivery
+type SSEOptions struct {
+	// HeartbeatInterval is how often a comment line is sent to keep idle connections open
+	// Default: 15 seconds
+	HeartbeatInterval time.Duration
+}
+
+// DefaultSSEOptions returns sensible defaults for most event streams
+func DefaultSSEOptions() SSEOptions {
+	return SSEOptions{
+		HeartbeatInterval: 15 * time.Second,
+	}
+}
+
+// SSEFromChannel adapts a channel of events to an SSEStream.
+// The stream ends when the channel is closed or the client disconnects.
+func SSEFromChannel[T any](events <-chan SSEEvent[T]) SSEStream[T] {
+	return func(ctx context.Context, lastEventID string, emit func(SSEEvent[T]) error) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case event, ok := <-events:
+				if !ok {
+					return nil
+				}
+				if err := emit(event); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// ServeSSE runs stream for the request, writing each emitted event and flushing immediately.
+//
+// It sends heartbeats while the stream is idle, passes the Last-Event-ID header to the stream
+// for resume, and stops when the client disconnects (r.Context() is done). A client disconnect
+// is not an error. Any other error returned by the stream is sent as an "error" event carrying
+// the APIError and returned wrapped in *StreamError, since the response is already committed.
+func ServeSSE[T any](w http.ResponseWriter, r *http.Request, stream SSEStream[T], opts SSEOptions) error {
+	if opts.HeartbeatInterval <= 0 {
+		opts.HeartbeatInterval = DefaultSSEOptions().HeartbeatInterval
+	}
+
+	controller := http.NewResponseController(w)
+	// Long-lived connection: lift any server write deadline (unsupported writers are ignored)
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", EventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	sw := &sseWriter{w: w, controller: controller}
+	if err := sw.flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// Heartbeats keep proxies from closing idle connections
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		ticker := time.NewTicker(opts.HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := sw.write(": heartbeat\n\n"); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	emit := func(event SSEEvent[T]) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		frame, err := formatSSEEvent(event.ID, event.Event, event.Data, event.Retry)
+		if err != nil {
+			return err
+		}
+		if err := sw.write(frame); err != nil {
+			cancel()
+			return err
+		}
+		sw.mu.Lock()
+		sw.count++
+		sw.mu.Unlock()
+		return nil
+	}
+
+	err := stream(ctx, r.Header.Get("Last-Event-ID"), emit)
+	cancel()
+	<-heartbeatDone
+
+	// Client went away - nothing left to report
+	if err == nil || r.Context().Err() != nil || errors.Is(err, context.Canceled) {
+		return nil
+	}
+
+	// Report the failure in-band, without leaking internal details
+	if frame, frameErr := formatSSEEvent("", "error", streamAPIError(r, err), 0); frameErr == nil {
+		sw.write(frame)
+	}
+	return &StreamError{Err: err, Items: sw.count}
+}
+
+// sseWriter serialises writes from the stream and the heartbeat goroutine
+type sseWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	count      int
+	mu         sync.Mutex
+}
+
+// write sends a frame and flushes it immediately
+func (sw *sseWriter) write(frame string) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if _, err := sw.w.Write([]byte(frame)); err != nil {
+		return err
+	}
+	if err := sw.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// flush pushes headers to the client before the first event
+func (sw *sseWriter) flush() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	return sw.controller.Flush()
+}
+
+// formatSSEEvent renders one event in the text/event-stream wire format
+func formatSSEEvent(id, event string, data any, retry time.Duration) (string, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if id != "" {
+		b.WriteString("id: " + sanitizeSSEField(id) + "\n")
+	}
+	if event != "" {
+		b.WriteString("event: " + sanitizeSSEField(event) + "\n")
+	}
+	if retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(retry.Milliseconds(), 10) + "\n")
+	}
+	b.WriteString("data: ")
+	b.Write(encoded)
+	b.WriteString("\n\n")
+	return b.String(), nil
+}
+
+// sanitizeSSEField strips line breaks, which would otherwise terminate the field early
+func sanitizeSSEField(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,260 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// NDJSONContentType is the media type for newline-delimited JSON streams
+const NDJSONContentType = "application/x-ndjson"
+
+// StreamErrorTrailer is the HTTP trailer set when a stream fails after the response has started
+const StreamErrorTrailer = "X-Stream-Error"
+
+// StreamOptions configures incremental list streaming
+type StreamOptions struct {
+	// FlushEvery flushes the response after this many items
+	// Default: 100
+	FlushEvery int
+
+	// FlushInterval flushes the response when this much time has passed since the last flush
+	// Default: 1 second
+	FlushInterval time.Duration
+}
+
+// DefaultStreamOptions returns sensible defaults for most list exports
+func DefaultStreamOptions() StreamOptions {
+	return StreamOptions{
+		FlushEvery:    100,
+		FlushInterval: time.Second,
+	}
+}
+
+// StreamError reports a failure that happened after the stream was committed.
+// The status line and part of the body have already been sent, so callers must not
+// write another error response; the failure is reported in-band and via StreamErrorTrailer.
+type StreamError struct {
+	Err   error
+	Items int // Number of items written before the failure
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("stream aborted after %d items: %v", e.Items, e.Err)
+}
+
+func (e *StreamError) Unwrap() error {
+	return e.Err
+}
+
+// Stream writes the items of seq incrementally, flushing periodically.
+//
+// The format is negotiated from the Accept header:
+//   - application/json (default): {"data": [...], "count": N}
+//   - application/x-ndjson: one JSON value per line
+//
+// An error yielded before the first item is returned unchanged so the caller can send a
+// normal error response. An error yielded later terminates the stream with an error object
+// ({"error": APIError} as the "error" member or as the final NDJSON line), sets the
+// X-Stream-Error trailer, and is returned wrapped in *StreamError.
+// Returns a 406 APIError if the client accepts neither format.
+func Stream[T any](w http.ResponseWriter, r *http.Request, status int, seq iter.Seq2[T, error], opts StreamOptions) error {
+	ndjson, ok := negotiateStreamFormat(r.Header.Get("Accept"))
+	if !ok {
+		return NewAPIError(http.StatusNotAcceptable, "Not Acceptable",
+			"Supported media types: application/json, "+NDJSONContentType)
+	}
+
+	defaults := DefaultStreamOptions()
+	if opts.FlushEvery <= 0 {
+		opts.FlushEvery = defaults.FlushEvery
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaults.FlushInterval
+	}
+
+	sw := &streamWriter{
+		w:          w,
+		r:          r,
+		controller: http.NewResponseController(w),
+		ndjson:     ndjson,
+		status:     status,
+		opts:       opts,
+		lastFlush:  time.Now(),
+	}
+
+	for item, err := range seq {
+		// Stop pulling items once the client is gone
+		if ctxErr := r.Context().Err(); ctxErr != nil {
+			return sw.abort(ctxErr)
+		}
+
+		if err != nil {
+			return sw.abort(err)
+		}
+
+		if err := sw.writeItem(item); err != nil {
+			return sw.abort(err)
+		}
+	}
+
+	if err := sw.finish(); err != nil {
+		return sw.abort(err)
+	}
+	return nil
+}
+
+// negotiateStreamFormat reports whether NDJSON was requested; false ok means neither format is acceptable
+func negotiateStreamFormat(accept string) (ndjson bool, ok bool) {
+	if accept == "" {
+		return false, true
+	}
+
+	for _, mediaRange := range parseAccept(accept) {
+		if mediaRange.mediaType == NDJSONContentType {
+			return true, true
+		}
+		if mediaRange.matches("application/json") {
+			return false, true
+		}
+	}
+
+	return false, false
+}
+
+// streamWriter tracks the state of an in-progress stream
+type streamWriter struct {
+	w          http.ResponseWriter
+	r          *http.Request
+	controller *http.ResponseController
+	ndjson     bool
+	status     int
+	opts       StreamOptions
+	started    bool
+	count      int
+	sinceFlush int
+	lastFlush  time.Time
+}
+
+// start commits the status line and opens the JSON envelope
+func (sw *streamWriter) start() error {
+	sw.started = true
+
+	contentType := "application/json"
+	if sw.ndjson {
+		contentType = NDJSONContentType
+	}
+	sw.w.Header().Set("Content-Type", contentType)
+	sw.w.Header().Add("Vary", "Accept")
+	sw.w.Header().Set("Trailer", StreamErrorTrailer)
+	sw.w.WriteHeader(sw.status)
+
+	if !sw.ndjson {
+		_, err := sw.w.Write([]byte(`{"data":[`))
+		return err
+	}
+	return nil
+}
+
+// writeItem encodes one item, starting the response on the first call
+func (sw *streamWriter) writeItem(item any) error {
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	if !sw.started {
+		if err := sw.start(); err != nil {
+			return err
+		}
+	}
+
+	if sw.ndjson {
+		encoded = append(encoded, '\n')
+	} else if sw.count > 0 {
+		encoded = append([]byte{','}, encoded...)
+	}
+	if _, err := sw.w.Write(encoded); err != nil {
+		return err
+	}
+
+	sw.count++
+	sw.sinceFlush++
+	if sw.sinceFlush >= sw.opts.FlushEvery || time.Since(sw.lastFlush) >= sw.opts.FlushInterval {
+		sw.flush()
+	}
+	return nil
+}
+
+// flush pushes buffered bytes to the client; writers without flush support are ignored
+func (sw *streamWriter) flush() {
+	_ = sw.controller.Flush()
+	sw.sinceFlush = 0
+	sw.lastFlush = time.Now()
+}
+
+// fail terminates a started stream with an error object and the error trailer
+func (sw *streamWriter) fail(err error) {
+	apiErr := streamAPIError(sw.r, err)
+	sw.w.Header().Set(StreamErrorTrailer, apiErr.Message)
+
+	if sw.ndjson {
+		// Final line: {"error":{...}}
+		line, _ := json.Marshal(map[string]any{"error": apiErr})
+		sw.w.Write(append(line, '\n'))
+	} else {
+		// Close the data array and add the error member: {"data":[...],"count":N,"error":{...}}
+		value, _ := json.Marshal(apiErr)
+		sw.w.Write([]byte(`],"count":` + strconv.Itoa(sw.count) + `,"error":`))
+		sw.w.Write(append(value, '}', '\n'))
+	}
+	sw.flush()
+}
+
+// abort ends the stream after err. Before the response has started, err is returned for a
+// normal error response; afterwards the response is committed, so err is reported in-band
+// and returned wrapped in *StreamError.
+func (sw *streamWriter) abort(err error) error {
+	if !sw.started {
+		return err
+	}
+	sw.fail(err)
+	return &StreamError{Err: err, Items: sw.count}
+}
+
+// finish closes the envelope; an empty sequence still produces a valid document
+func (sw *streamWriter) finish() error {
+	if !sw.started {
+		if err := sw.start(); err != nil {
+			return err
+		}
+	}
+
+	if !sw.ndjson {
+		if _, err := sw.w.Write([]byte(`],"count":` + strconv.Itoa(sw.count) + "}\n")); err != nil {
+			return err
+		}
+	}
+	sw.flush()
+	return nil
+}
+
+// streamAPIError converts a mid-stream error to the APIError reported in-band, logged and
+// redacted as WriteError would: unexpected errors go through InternalError, and the detail of
+// 5xx APIErrors is withheld in ErrorModeProduction.
+func streamAPIError(r *http.Request, err error) APIError {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return InternalError(r, err)
+	}
+	cause := apiErr.Cause
+	if error(apiErr) != err {
+		cause = err
+	}
+	redacted := redactAPIError(r, *apiErr)
+	logAPIError(r, *apiErr, redacted, cause)
+	return redacted
+}
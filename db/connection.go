@@ -80,5 +80,13 @@ func HealthCheck(db *sql.DB) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	return HealthCheckContext(ctx, db)
+}
+
+// HealthCheckContext pings the database, giving up when ctx is done
+func HealthCheckContext(ctx context.Context, db *sql.DB) error {
+	if db == nil {
+		return fmt.Errorf("database is not configured")
+	}
 	return db.PingContext(ctx)
 }
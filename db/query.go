@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"iter"
 	"log/slog"
 	"reflect"
 
@@ -64,6 +65,39 @@ func QueryMany[T any](ctx context.Context, querier Querier, query string, args .
 	return results, err
 }
 
+// QueryIter executes a query and yields rows one at a time instead of materialising a slice.
+// Rows are scanned lazily as the sequence is ranged over and closed when iteration stops.
+// A query or scan error is yielded once as the final element.
+// The provided context is used for cancellation and timeout support.
+func QueryIter[T any](ctx context.Context, querier Querier, query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		rows, err := querier.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(zero, fmt.Errorf("query failed: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		scanner := sqlscan.NewRowScanner(rows)
+		for rows.Next() {
+			var row T
+			if err := scanner.Scan(&row); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
 // QueryOne executes a single row query with positional parameters and uses automatic struct scanning.
 // The provided context is used for cancellation and timeout support.
 func QueryOne[T any](ctx context.Context, querier Querier, query string, args ...any) (T, error) {
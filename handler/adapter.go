@@ -33,7 +33,7 @@ func AdaptHandler[ParamTypeT any, BodyTypeT any, ResponseBodyT any](
 	logger *slog.Logger,
 	handler Handler[ParamTypeT, BodyTypeT, ResponseBodyT],
 ) http.HandlerFunc {
-	return adaptHandler(db, logger, nil, handler)
+	return adaptHandler(db, logger, registrationConfig{}, handler)
 }
 
 // AdaptHandlerWithServices converts a typed Handler to http.HandlerFunc with service injection.
@@ -52,69 +52,138 @@ func AdaptHandlerWithServices[ParamTypeT any, BodyTypeT any, ResponseBodyT any](
 	services any,
 	handler Handler[ParamTypeT, BodyTypeT, ResponseBodyT],
 ) http.HandlerFunc {
-	return adaptHandler(db, logger, services, handler)
+	return adaptHandler(db, logger, registrationConfig{services: services}, handler)
 }
 
-// adaptHandler is the shared implementation for both AdaptHandler and AdaptHandlerWithServices.
+// AdaptHandlerWithOptions converts a typed Handler to http.HandlerFunc using the same
+// RegistrationOption values accepted by Registry.RegisterWithRouter.
+// Use it for handlers mounted directly on a router outside a Registry.
+//
+// Example:
+//
+//	r.Get("/users/{id}", handler.AdaptHandlerWithOptions(db, logger, h,
+//	    handler.WithServices(appServices),
+//	    handler.WithErrorFormat(core.ErrorFormatProblem),
+//	))
+func AdaptHandlerWithOptions[ParamTypeT any, BodyTypeT any, ResponseBodyT any](
+	db *sql.DB,
+	logger *slog.Logger,
+	handler Handler[ParamTypeT, BodyTypeT, ResponseBodyT],
+	opts ...RegistrationOption,
+) http.HandlerFunc {
+	return adaptHandler(db, logger, newRegistrationConfig(opts), handler)
+}
+
+// adaptHandler is the shared implementation for all AdaptHandler variants.
 func adaptHandler[ParamTypeT any, BodyTypeT any, ResponseBodyT any](
 	db *sql.DB,
 	logger *slog.Logger,
-	services any,
+	cfg registrationConfig,
 	handler Handler[ParamTypeT, BodyTypeT, ResponseBodyT],
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Extract request context for cancellation and timeout support
-		requestCtx := r.Context()
+		r = cfg.withRequestContext(r)
+
+		// Recover panics as JSON errors even when mounted outside the default router
+		core.ServeRecovered(w, r, func(w http.ResponseWriter, r *http.Request) {
+			serveTyped(db, logger, cfg, handler, w, r)
+		})
+	}
+}
 
-		// Log database connection status for debugging
-		logger.Debug("AdaptHandler creating context",
-			"db_nil", db == nil,
-			"path", r.URL.Path,
-		)
+// withRequestContext selects the error response format, error mode and message catalog
+// of the registration for r, where configured
+func (cfg registrationConfig) withRequestContext(r *http.Request) *http.Request {
+	if cfg.errorFormat != "" {
+		r = r.WithContext(core.ContextWithErrorFormat(r.Context(), cfg.errorFormat))
+	}
+	if cfg.errorMode != "" {
+		r = r.WithContext(core.ContextWithErrorMode(r.Context(), cfg.errorMode))
+	}
+	if cfg.messageCatalog != nil {
+		r = r.WithContext(core.ContextWithMessageCatalog(r.Context(), cfg.messageCatalog))
+	}
+	return r
+}
 
+// serveTyped runs a typed handler for one request and writes its error, if any
+func serveTyped[ParamTypeT any, BodyTypeT any, ResponseBodyT any](
+	db *sql.DB,
+	logger *slog.Logger,
+	cfg registrationConfig,
+	handler Handler[ParamTypeT, BodyTypeT, ResponseBodyT],
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	// Log database connection status for debugging
+	logger.Debug("AdaptHandler creating context",
+		"db_nil", db == nil,
+		"path", r.URL.Path,
+	)
+
+	run := func(w http.ResponseWriter, r *http.Request) error {
 		// Create handler context with application dependencies and request context
 		ctx := HandlerContext[ParamTypeT, BodyTypeT]{
-			Context:     requestCtx, // Propagate HTTP request context
+			Context:     r.Context(), // Propagate HTTP request context for cancellation and timeout support
 			DB:          db,
 			Logger:      logger,
-			Services:    services,
+			Services:    cfg.services,
 			UserUUID:    Nil[uuid.UUID](), // No auth by default
 			CompanyUUID: Nil[uuid.UUID](), // No auth by default
 		}
-
-		// Execute the handler and handle response/errors
 		_, err := handler(ctx, w, r)
-		if err != nil {
-			// Handle context-specific errors
-			if errors.Is(err, context.Canceled) {
-				// Client disconnected - don't write response
-				logger.Info("Request cancelled by client", "path", r.URL.Path)
-				return
-			}
-			if errors.Is(err, context.DeadlineExceeded) {
-				// Request timeout
-				logger.Error("Request timeout", "path", r.URL.Path)
-				core.WriteAPIError(w, r, *core.NewAPIError(
-					http.StatusGatewayTimeout,
-					"Request timeout",
-				))
-				return
-			}
-
-			// Log the error for debugging
-			logger.Error("Handler error", "error", err.Error(), "path", r.URL.Path)
+		return err
+	}
 
-			// Write appropriate error response based on error type
-			if apiErr, ok := err.(*core.APIError); ok {
-				core.WriteAPIError(w, r, *apiErr)
-			} else {
-				// Fallback for unexpected errors
-				core.Error(w, r, http.StatusInternalServerError, "Internal server error")
-			}
+	// Execute the handler and handle response/errors; provided values outlive the whole chain
+	var err error
+	if cfg.dependencies != nil {
+		err = serveWithDependencies(cfg.dependencies, logger, w, r, run)
+	} else {
+		err = run(w, r)
+	}
+	if err != nil {
+		// Handle context-specific errors
+		if errors.Is(err, context.Canceled) {
+			// Client disconnected - don't write response
+			logger.Info("Request cancelled by client", "path", r.URL.Path)
 			return
 		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			// Request timeout
+			logger.Error("Request timeout", "path", r.URL.Path)
+			core.WriteAPIError(w, r, *core.NewAPIError(
+				http.StatusGatewayTimeout,
+				"Request timeout",
+			))
+			return
+		}
+
+		// Log the error for debugging
+		logger.Error("Handler error", "error", err.Error(), "path", r.URL.Path)
+
+		// Write appropriate error response based on error type
+		if _, ok := core.AsAPIError(err); ok {
+			// APIErrors keep their status even when wrapped; the wrapping error is logged as the cause
+			core.WriteError(w, r, err)
+		} else if apiErr, ok := translatePgError(cfg, err); ok {
+			// PostgreSQL error mapped by SQLSTATE/constraint - raw message stays in the log only
+			core.WriteAPIError(w, r, *apiErr)
+		} else {
+			// Fallback for unexpected errors - redacted according to the request's error mode
+			core.WriteError(w, r, err)
+		}
+		return
+	}
+
+	// Success: Response handling is now delegated to middleware (e.g., ResponseJSON)
+	// The handler chain is responsible for writing the response
+}
 
-		// Success: Response handling is now delegated to middleware (e.g., ResponseJSON)
-		// The handler chain is responsible for writing the response
+// translatePgError maps PostgreSQL errors to APIErrors when a translator is configured
+func translatePgError(cfg registrationConfig, err error) (*core.APIError, bool) {
+	if cfg.pgErrorTranslator == nil {
+		return nil, false
 	}
+	return cfg.pgErrorTranslator.Translate(err)
 }
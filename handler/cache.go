@@ -0,0 +1,43 @@
+package handler
+
+import "net/http"
+
+// withCacheControl applies a route's Cache-Control policy to successful responses.
+// Error responses are left alone so failures are never cached under the route's policy.
+func withCacheControl(policy string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(&cacheControlWriter{ResponseWriter: w, policy: policy}, r)
+	}
+}
+
+// cacheControlWriter sets Cache-Control when the status is committed
+type cacheControlWriter struct {
+	http.ResponseWriter
+	policy      string
+	wroteHeader bool
+}
+
+func (cw *cacheControlWriter) WriteHeader(status int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		if (status >= 200 && status < 300) || status == http.StatusNotModified {
+			// Handlers may still override the route policy for individual responses
+			if cw.Header().Get("Cache-Control") == "" {
+				cw.Header().Set("Cache-Control", cw.policy)
+			}
+		}
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *cacheControlWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController (flushing, deadlines)
+func (cw *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
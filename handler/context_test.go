@@ -1,16 +1,22 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/platform-smith-labs/japi-core/v3/core"
 )
 
@@ -19,15 +25,18 @@ func TestAdapterContextExtraction(t *testing.T) {
 	t.Run("extracts context from request", func(t *testing.T) {
 		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-		var capturedContext context.Context
+		var capturedContext, requestContext context.Context
 		handler := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
 			capturedContext = ctx.Context
+			requestContext = r.Context()
 			return struct{}{}, nil
 		}
 
 		adapted := AdaptHandler[struct{}, struct{}, struct{}](nil, logger, handler)
 
-		req := httptest.NewRequest("GET", "/test", nil)
+		// The adapter adds its recovery scope, so the context is derived from the request's
+		reqCtx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest("GET", "/test", nil).WithContext(reqCtx)
 		w := httptest.NewRecorder()
 
 		adapted(w, req)
@@ -35,9 +44,13 @@ func TestAdapterContextExtraction(t *testing.T) {
 		if capturedContext == nil {
 			t.Error("Expected context to be set in HandlerContext")
 		}
-		if capturedContext != req.Context() {
+		if capturedContext != requestContext {
 			t.Error("Expected context to match request context")
 		}
+		cancel()
+		if capturedContext.Err() == nil {
+			t.Error("Expected context to be derived from the request context")
+		}
 	})
 
 	t.Run("propagates request context values", func(t *testing.T) {
@@ -422,3 +435,310 @@ func BenchmarkContextCancellationCheck(b *testing.B) {
 		adapted(w, req)
 	}
 }
+
+// TestAdaptHandlerWithOptions_ErrorFormat verifies the WithErrorFormat registration option
+func TestAdaptHandlerWithOptions_ErrorFormat(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		return struct{}{}, core.NewValidationError("Validation failed").AddField("email", "email is required")
+	}
+
+	adapted := AdaptHandlerWithOptions[struct{}, struct{}, struct{}](nil, logger, handler, WithErrorFormat(core.ErrorFormatProblem))
+	req := httptest.NewRequest("POST", "/test", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	w := httptest.NewRecorder()
+	adapted.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != core.ProblemContentType {
+		t.Errorf("Expected Content-Type %q, got %q", core.ProblemContentType, ct)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if body["instance"] != "req-123" {
+		t.Errorf("Expected instance 'req-123', got %v", body["instance"])
+	}
+	fields, ok := body["fields"].(map[string]any)
+	if !ok || fields["email"] != "email is required" {
+		t.Errorf("Expected fields extension with email error, got %v", body["fields"])
+	}
+}
+
+// TestAdaptHandlerWithOptions_PgErrorTranslator verifies PostgreSQL errors are translated to APIErrors
+func TestAdaptHandlerWithOptions_PgErrorTranslator(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	translator := core.NewPgErrorTranslator().
+		MapConstraint("users_email_key", core.ConstraintMapping{Field: "email", Message: "email is already registered"})
+
+	tests := []struct {
+		name       string
+		pgErr      *pgconn.PgError
+		wantStatus int
+		wantHeader string
+	}{
+		{"unique violation with override", &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key", Message: "duplicate key"}, http.StatusConflict, ""},
+		{"foreign key violation", &pgconn.PgError{Code: "23503"}, http.StatusConflict, ""},
+		{"check violation", &pgconn.PgError{Code: "23514"}, http.StatusUnprocessableEntity, ""},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, http.StatusServiceUnavailable, "1"},
+		{"query canceled", &pgconn.PgError{Code: "57014"}, http.StatusGatewayTimeout, ""},
+		{"other error", &pgconn.PgError{Code: "XX000", Message: "secret internals"}, http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+				return struct{}{}, fmt.Errorf("insert user: %w", tt.pgErr)
+			}
+
+			adapted := AdaptHandlerWithOptions[struct{}, struct{}, struct{}](nil, logger, handler, WithPgErrorTranslator(translator))
+			req := httptest.NewRequest("POST", "/test", nil)
+			w := httptest.NewRecorder()
+			adapted.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantHeader {
+				t.Errorf("Expected Retry-After %q, got %q", tt.wantHeader, got)
+			}
+			if strings.Contains(w.Body.String(), tt.pgErr.Message) && tt.pgErr.Message != "" {
+				t.Errorf("Response leaked the raw PostgreSQL message: %s", w.Body.String())
+			}
+		})
+	}
+
+	t.Run("constraint override adds field error", func(t *testing.T) {
+		handler := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}
+		}
+
+		adapted := AdaptHandlerWithOptions[struct{}, struct{}, struct{}](nil, logger, handler, WithPgErrorTranslator(translator))
+		req := httptest.NewRequest("POST", "/test", nil)
+		w := httptest.NewRecorder()
+		adapted.ServeHTTP(w, req)
+
+		var body struct {
+			Error core.APIError `json:"error"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON body: %v", err)
+		}
+		if body.Error.Fields["email"] != "email is already registered" {
+			t.Errorf("Expected email field error, got %v", body.Error.Fields)
+		}
+	})
+}
+
+// TestAdaptHandlerWithOptions_ErrorMode verifies unexpected errors are redacted unless development mode is selected
+func TestAdaptHandlerWithOptions_ErrorMode(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		return struct{}{}, errors.New("open /etc/app/secrets.yaml: permission denied")
+	}
+
+	tests := []struct {
+		name       string
+		opts       []RegistrationOption
+		wantDetail bool
+	}{
+		{"production by default", nil, false},
+		{"development", []RegistrationOption{WithErrorMode(core.ErrorModeDevelopment)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapted := AdaptHandlerWithOptions[struct{}, struct{}, struct{}](nil, logger, handler, tt.opts...)
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("X-Request-ID", "req-456")
+			w := httptest.NewRecorder()
+			adapted.ServeHTTP(w, req)
+
+			if w.Code != http.StatusInternalServerError {
+				t.Fatalf("Expected status 500, got %d", w.Code)
+			}
+			if got := strings.Contains(w.Body.String(), "secrets.yaml"); got != tt.wantDetail {
+				t.Errorf("Expected detail exposed=%v, body %s", tt.wantDetail, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), `"correlation_id":"req-456"`) {
+				t.Errorf("Expected correlation ID from request ID, body %s", w.Body.String())
+			}
+		})
+	}
+}
+
+// TestAdaptHandlerRecoversPanics verifies typed handlers recover panics without the default router
+func TestAdaptHandlerRecoversPanics(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		var users map[string]int
+		users["alice"] = 1
+		return struct{}{}, nil
+	}
+
+	adapted := AdaptHandlerWithOptions[struct{}, struct{}, struct{}](nil, logger, handler, WithErrorFormat(core.ErrorFormatProblem))
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Request-ID", "req-789")
+	w := httptest.NewRecorder()
+	adapted.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != core.ProblemContentType {
+		t.Errorf("Expected Content-Type %q, got %q", core.ProblemContentType, ct)
+	}
+	if strings.Contains(w.Body.String(), "nil map") {
+		t.Errorf("Panic value leaked in production mode: %s", w.Body.String())
+	}
+}
+
+type panicRecorder struct {
+	paths []string
+}
+
+func (p *panicRecorder) ObservePanic(r *http.Request) {
+	p.paths = append(p.paths, r.URL.Path)
+}
+
+// TestAdaptRecoversPanicsWithUser verifies panics of a handler adapted outside the default router keep the request's user
+func TestAdaptRecoversPanicsWithUser(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(previous)
+
+	th := TypedHandler[struct{}, struct{}, struct{}]{handler: func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		core.SetRequestUser(ctx.Context, "user-42")
+		panic("boom")
+	}}
+	adapted := th.Adapt(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	observer := &panicRecorder{}
+	req := core.WithPanicObserver(httptest.NewRequest("GET", "/test", nil), observer)
+	w := httptest.NewRecorder()
+	adapted.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", w.Code)
+	}
+	if !strings.Contains(logs.String(), "user=user-42") {
+		t.Errorf("Expected the panic log to name the user, got %s", logs.String())
+	}
+	if len(observer.paths) != 1 {
+		t.Errorf("Expected the panic observer to be notified, got %v", observer.paths)
+	}
+
+	// Without an observer installed upstream the adapter provides the scope itself
+	logs.Reset()
+	adapted.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	if !strings.Contains(logs.String(), "user=user-42") {
+		t.Errorf("Expected the panic log to name the user, got %s", logs.String())
+	}
+}
+
+// TestAdaptHandler_WrappedAPIError verifies typed handlers honour APIErrors anywhere in the error chain
+func TestAdaptHandler_WrappedAPIError(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cause := errors.New("upstream returned 503")
+	apiErr := core.Wrap(cause, http.StatusBadGateway, "Billing unavailable")
+
+	if !errors.Is(apiErr, cause) {
+		t.Fatal("Expected errors.Is to find the wrapped cause")
+	}
+
+	handler := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		return struct{}{}, fmt.Errorf("charge invoice: %w", apiErr)
+	}
+
+	adapted := AdaptHandler[struct{}, struct{}, struct{}](nil, logger, handler)
+	w := httptest.NewRecorder()
+	adapted(w, httptest.NewRequest("POST", "/test", nil))
+
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502, got %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "upstream returned") {
+		t.Errorf("Cause leaked to client: %s", w.Body.String())
+	}
+}
+
+// TestServices verifies the typed services accessors and the startup check
+func TestServices(t *testing.T) {
+	type AppServices struct{ Name string }
+	type OtherServices struct{}
+
+	t.Run("handler context", func(t *testing.T) {
+		ctx := HandlerContext[struct{}, struct{}]{Services: AppServices{Name: "app"}}
+		if got := Services[AppServices](ctx); got.Name != "app" {
+			t.Errorf("expected Name='app', got %q", got.Name)
+		}
+		if _, ok := LookupServices[OtherServices](ctx); ok {
+			t.Error("expected LookupServices to report a mismatch")
+		}
+	})
+
+	t.Run("hook context", func(t *testing.T) {
+		ctx := HookContext{Services: &AppServices{Name: "app"}}
+		if got, ok := LookupServices[*AppServices](ctx); !ok || got.Name != "app" {
+			t.Errorf("expected *AppServices, got %v, %v", got, ok)
+		}
+	})
+
+	t.Run("mismatch panics with both types", func(t *testing.T) {
+		defer func() {
+			err, ok := recover().(error)
+			if !ok {
+				t.Fatal("expected Services to panic with an error")
+			}
+			if !strings.Contains(err.Error(), "AppServices") || !strings.Contains(err.Error(), "OtherServices") {
+				t.Errorf("expected both types in %q", err)
+			}
+		}()
+		Services[OtherServices](HandlerContext[struct{}, struct{}]{Services: AppServices{}})
+	})
+
+	t.Run("RequireServices checks at registration", func(t *testing.T) {
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		newRegistry := func() *Registry {
+			reg := NewRegistry()
+			MakeHandler(reg, RouteInfo{Method: "GET", Path: "/ping"},
+				func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+					return struct{}{}, nil
+				})
+			RequireServices[AppServices](reg)
+			return reg
+		}
+
+		if err := newRegistry().RegisterWithRouter(chi.NewRouter(), nil, logger, WithServices(AppServices{})); err != nil {
+			t.Errorf("expected matching services to register, got %v", err)
+		}
+
+		for name, opts := range map[string][]RegistrationOption{
+			"wrong type": {WithServices(OtherServices{})},
+			"missing":    nil,
+		} {
+			r := chi.NewRouter()
+			err := newRegistry().RegisterWithRouter(r, nil, logger, opts...)
+			if err == nil || !strings.Contains(err.Error(), "AppServices") {
+				t.Errorf("%s: expected a mismatch error, got %v", name, err)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest("GET", "/ping", nil))
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("%s: expected no route to be registered, got %d", name, rec.Code)
+			}
+		}
+
+		// Checks of a mounted module apply to the registry it is mounted in
+		api := NewRegistry()
+		api.Mount("/users", newRegistry())
+		err := api.RegisterWithRouter(chi.NewRouter(), nil, logger, WithServices(OtherServices{}))
+		if err == nil || !strings.Contains(err.Error(), "GET /users/ping") {
+			t.Errorf("expected the mounted route in the mismatch error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DeprecationObserver is notified of every request served by a deprecated or sunsetting route
+// (e.g. metrics.DeprecationMetrics counts them per client)
+type DeprecationObserver interface {
+	ObserveDeprecatedCall(method, path, client string)
+}
+
+// WithDeprecationObserver reports calls to routes marked Deprecated or with a Sunset date,
+// so remaining callers can be found before the route is removed. A call is attributed to the
+// User-Agent product (e.g. "billing-service" for "billing-service/2.3") when it is one of
+// clients, and to "other" otherwise, so a caller cannot create new metric labels.
+//
+// Usage:
+//
+//	registry.RegisterWithRouter(r, db, logger,
+//	    handler.WithDeprecationObserver(metrics.NewDeprecationMetrics(metrics.DefaultMetricsOptions()),
+//	        "billing-service", "mobile-app"))
+func WithDeprecationObserver(observer DeprecationObserver, clients ...string) RegistrationOption {
+	known := make(map[string]bool, len(clients))
+	for _, client := range clients {
+		known[client] = true
+	}
+	return func(cfg *registrationConfig) {
+		cfg.deprecationObserver = observer
+		cfg.deprecationClients = known
+	}
+}
+
+// IsDeprecated reports whether the route is deprecated or has a sunset date
+func (info RouteInfo) IsDeprecated() bool {
+	return info.Deprecated || !info.DeprecatedAt.IsZero() || !info.Sunset.IsZero()
+}
+
+// withDeprecation sends the Deprecation (RFC 9745), Sunset (RFC 8594) and successor-version
+// Link headers of a route on every response and reports the call to observer
+func withDeprecation(info RouteInfo, observer DeprecationObserver, clients map[string]bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case !info.DeprecatedAt.IsZero():
+			w.Header().Set("Deprecation", "@"+strconv.FormatInt(info.DeprecatedAt.Unix(), 10))
+		case info.Deprecated:
+			w.Header().Set("Deprecation", "true")
+		}
+		if !info.Sunset.IsZero() {
+			w.Header().Set("Sunset", info.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if info.Successor != "" {
+			w.Header().Add("Link", "<"+info.Successor+`>; rel="successor-version"`)
+		}
+
+		if observer != nil {
+			observer.ObserveDeprecatedCall(info.Method, info.Path, deprecationClient(r, clients))
+		}
+
+		next(w, r)
+	}
+}
+
+// deprecationClient names the caller by the product token of its User-Agent
+// (e.g. "billing-service" for "billing-service/2.3 (+https://...)") if it is a known client.
+// The User-Agent is chosen by the caller, so any other product is reported as "other".
+func deprecationClient(r *http.Request, clients map[string]bool) string {
+	product, _, _ := strings.Cut(strings.TrimSpace(r.UserAgent()), " ")
+	product, _, _ = strings.Cut(product, "/")
+	if !clients[product] {
+		return "other"
+	}
+	return product
+}
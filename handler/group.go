@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Registrar collects routes created by MakeHandler. It is implemented by *Registry and *Group.
+type Registrar interface {
+	register(route PendingRoute)
+}
+
+// Group registers routes into a Registry under a shared path prefix, with default tags,
+// default security, HTTP middleware and hooks applied to every route registered through it.
+// Create one with Registry.Group and nest further groups with Group.Group.
+//
+// Example:
+//
+//	v1 := reg.Group("/api/v1", handler.WithTags("Users"), handler.WithSecurity("BearerAuth"),
+//	    handler.WithMiddleware(middleware.Timeout(5*time.Second)))
+//	admin := v1.Group("/admin", handler.WithTags("Admin"))
+//
+//	var _ = handler.MakeHandler(v1, RouteInfo{Method: "GET", Path: "/users"}, ListUsers, typed.ResponseJSON)      // GET /api/v1/users
+//	var _ = handler.MakeHandler(admin, RouteInfo{Method: "DELETE", Path: "/users/{id}"}, DeleteUser, typed.ParseParams, typed.ResponseJSON) // DELETE /api/v1/admin/users/{id}
+type Group struct {
+	registry   *Registry
+	prefix     string
+	tags       []string
+	security   []string
+	middleware []func(http.Handler) http.Handler
+	hooks      []Hook
+}
+
+// GroupOption configures a Group
+type GroupOption func(*Group)
+
+// WithTags sets the Swagger tags of routes that declare none in their RouteInfo
+func WithTags(tags ...string) GroupOption {
+	return func(g *Group) {
+		g.tags = tags
+	}
+}
+
+// WithSecurity sets the Swagger security schemes (e.g. "BearerAuth") of routes that declare
+// none in their RouteInfo. It documents the requirement only; enforce it with middleware.
+func WithSecurity(schemes ...string) GroupOption {
+	return func(g *Group) {
+		g.security = schemes
+	}
+}
+
+// WithMiddleware wraps every route of the group (and of its nested groups) with HTTP middleware.
+// Middleware runs in the order given, before the route's typed middleware chain;
+// middleware of an outer group runs before that of an inner group.
+func WithMiddleware(middleware ...func(http.Handler) http.Handler) GroupOption {
+	return func(g *Group) {
+		g.middleware = append(g.middleware, middleware...)
+	}
+}
+
+// Group returns a registrar that adds prefix, default tags, default security, middleware and
+// hooks to every route registered through it
+func (reg *Registry) Group(prefix string, opts ...GroupOption) *Group {
+	return newGroup(reg, &Group{prefix: prefix}, opts)
+}
+
+// Group returns a nested group. The prefix is appended to this group's prefix, middleware and
+// hooks are appended to this group's, and tags and security are inherited unless overridden.
+func (g *Group) Group(prefix string, opts ...GroupOption) *Group {
+	return newGroup(g.registry, &Group{
+		prefix:     joinRoutePath(g.prefix, prefix),
+		tags:       g.tags,
+		security:   g.security,
+		middleware: append([]func(http.Handler) http.Handler(nil), g.middleware...),
+		hooks:      append([]Hook(nil), g.hooks...),
+	}, opts)
+}
+
+// Prefix returns the group's full path prefix
+func (g *Group) Prefix() string {
+	return g.prefix
+}
+
+// newGroup binds g, holding the inherited settings, to reg and applies opts
+func newGroup(reg *Registry, g *Group, opts []GroupOption) *Group {
+	g.registry = reg
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// register applies the group's prefix and defaults to route and adds it to the registry
+func (g *Group) register(route PendingRoute) {
+	route.Path = joinRoutePath(g.prefix, route.Path)
+	route.RouteInfo.Path = route.Path
+	if len(route.RouteInfo.Tags) == 0 && len(g.tags) > 0 {
+		route.RouteInfo.Tags = append([]string(nil), g.tags...)
+	}
+	if len(route.RouteInfo.Security) == 0 && len(g.security) > 0 {
+		route.RouteInfo.Security = append([]string(nil), g.security...)
+	}
+
+	// Group middleware and hooks wrap those of the route, so they go first
+	route.httpMiddleware = append(append([]func(http.Handler) http.Handler(nil), g.middleware...), route.httpMiddleware...)
+	route.hooks = append(append([]Hook(nil), g.hooks...), route.hooks...)
+
+	g.registry.register(route)
+}
+
+// joinRoutePath appends a route path to a group prefix, e.g. "/api/v1" + "/users" = "/api/v1/users"
+func joinRoutePath(prefix, path string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if path == "" {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return prefix + path
+}
+
+// applyHTTPMiddleware wraps h so the first middleware runs first
+func applyHTTPMiddleware(h http.HandlerFunc, middleware []func(http.Handler) http.Handler) http.HandlerFunc {
+	if len(middleware) == 0 {
+		return h
+	}
+	var wrapped http.Handler = h
+	for i := len(middleware) - 1; i >= 0; i-- {
+		wrapped = middleware[i](wrapped)
+	}
+	return wrapped.ServeHTTP
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// HookContext is the type-agnostic part of a HandlerContext, passed to hooks.
+// Changes a hook makes to it before calling next are visible to the route's handler.
+type HookContext struct {
+	Context  context.Context
+	DB       *sql.DB
+	Logger   *slog.Logger
+	Services any
+
+	RequestID   Nullable[string]
+	UserUUID    Nullable[uuid.UUID]
+	CompanyUUID Nullable[uuid.UUID]
+
+	Route RouteInfo // Metadata of the route being served
+}
+
+// HookHandler is the rest of a route's handler chain as seen by a hook.
+// It returns the route's response body (nil on error) and error.
+type HookHandler func(ctx HookContext, w http.ResponseWriter, r *http.Request) (any, error)
+
+// Hook is typed middleware that works with every route, regardless of its parameter, body and
+// response types. Register hooks for all routes with Registry.Use, or for a group with WithHooks.
+// Hooks wrap the base handler: they run after the route's own middleware, so they see the
+// UserUUID and CompanyUUID set by RequireAuth and the parsed Params and Body.
+//
+// Requests that the route's middleware rejects (a 401 from RequireAuth, a 400 from ParseParams or
+// ParseBody) never reach hooks. This is deliberate: hooks only see requests the route accepted.
+// Work that must cover every request, such as access logging or metrics, belongs in HTTP middleware
+// registered on the router instead.
+//
+// Example:
+//
+//	func Audit(next handler.HookHandler) handler.HookHandler {
+//	    return func(ctx handler.HookContext, w http.ResponseWriter, r *http.Request) (any, error) {
+//	        response, err := next(ctx, w, r)
+//	        ctx.Logger.Info("audit", "route", ctx.Route.Path, "user", ctx.UserUUID.ValueOrDefault(), "failed", err != nil)
+//	        return response, err
+//	    }
+//	}
+type Hook func(next HookHandler) HookHandler
+
+// Use adds hooks that wrap the base handler of every route of the registry when RegisterWithRouter
+// runs, including routes registered before the call. Hooks run in the order given, outside group hooks.
+// Like all hooks they run inside the route's middleware and do not see requests that middleware rejects.
+// Their names are recorded in the MiddlewareNames returned by GetRoutes.
+func (reg *Registry) Use(hooks ...Hook) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.hooks = append(reg.hooks, hooks...)
+}
+
+// WithHooks wraps every route of the group (and of its nested groups) with hooks.
+// Hooks of an outer group run before those of an inner group.
+func WithHooks(hooks ...Hook) GroupOption {
+	return func(g *Group) {
+		g.hooks = append(g.hooks, hooks...)
+	}
+}
+
+// hookable is implemented by TypedHandler to wrap its handler with hooks
+type hookable interface {
+	withHooks(route RouteInfo, hooks []Hook) AdaptableHandler
+}
+
+// withHooks returns a copy of the typed handler with hooks wrapped around its base handler,
+// the first running first. Handlers built without MakeHandler have their whole chain wrapped.
+func (th TypedHandler[ParamTypeT, BodyTypeT, ResponseBodyT]) withHooks(route RouteInfo, hooks []Hook) AdaptableHandler {
+	if th.base == nil {
+		return TypedHandler[ParamTypeT, BodyTypeT, ResponseBodyT]{handler: applyHooks(th.handler, route, hooks)}
+	}
+	base := applyHooks(th.base, route, hooks)
+	return TypedHandler[ParamTypeT, BodyTypeT, ResponseBodyT]{
+		handler:    applyMiddleware(base, th.middleware),
+		base:       base,
+		middleware: th.middleware,
+	}
+}
+
+// applyHooks wraps next so that hooks run around it, exchanging the non-generic context fields
+func applyHooks[ParamTypeT any, BodyTypeT any, ResponseBodyT any](
+	next Handler[ParamTypeT, BodyTypeT, ResponseBodyT],
+	route RouteInfo,
+	hooks []Hook,
+) Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+	if len(hooks) == 0 {
+		return next
+	}
+
+	return func(ctx HandlerContext[ParamTypeT, BodyTypeT], w http.ResponseWriter, r *http.Request) (ResponseBodyT, error) {
+		var response ResponseBodyT
+		chain := HookHandler(func(hookCtx HookContext, w http.ResponseWriter, r *http.Request) (any, error) {
+			ctx.Context = hookCtx.Context
+			ctx.DB = hookCtx.DB
+			ctx.Logger = hookCtx.Logger
+			ctx.Services = hookCtx.Services
+			ctx.RequestID = hookCtx.RequestID
+			ctx.UserUUID = hookCtx.UserUUID
+			ctx.CompanyUUID = hookCtx.CompanyUUID
+
+			var err error
+			response, err = next(ctx, w, r)
+			if err != nil {
+				return nil, err
+			}
+			return response, nil
+		})
+		for i := len(hooks) - 1; i >= 0; i-- {
+			chain = hooks[i](chain)
+		}
+
+		hookCtx := ctx.hookContext()
+		hookCtx.Route = route
+		_, err := chain(hookCtx, w, r)
+		return response, err
+	}
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+)
+
+// DebugRoutesPath is where WithDebugRoutes serves the route table
+const DebugRoutesPath = "/_debug/routes"
+
+// RouteDescription is the audit view of a registered route, as served by WithDebugRoutes
+// and printed by WriteRoutes
+type RouteDescription struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Version    string   `json:"version,omitempty"`
+	Handler    string   `json:"handler"`            // Name of the base handler function passed to MakeHandler
+	Params     string   `json:"params,omitempty"`   // Param type, empty for struct{}
+	Body       string   `json:"body,omitempty"`     // Body type, empty for struct{}
+	Response   string   `json:"response,omitempty"` // Response type, empty for struct{}
+	Middleware []string `json:"middleware"`         // Applied middleware and hooks, innermost first
+	Auth       bool     `json:"auth"`               // RequireAuth is applied or security is declared
+	Security   []string `json:"security,omitempty"`
+	Deprecated bool     `json:"deprecated,omitempty"`
+}
+
+// RouteFormat selects the output of WriteRoutes
+type RouteFormat string
+
+const (
+	RouteFormatJSON  RouteFormat = "json"  // Indented JSON array of RouteDescription
+	RouteFormatTable RouteFormat = "table" // Aligned plain-text table
+)
+
+// RouteGuard decides whether a request may read the route table
+type RouteGuard func(r *http.Request) bool
+
+// RequireDebugToken returns a guard that admits requests sending "Authorization: Bearer <token>".
+// An empty token admits no request.
+func RequireDebugToken(token string) RouteGuard {
+	return func(r *http.Request) bool {
+		bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return ok && token != "" && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+	}
+}
+
+// WithDebugRoutes makes RegisterWithRouter serve the route table at GET DebugRoutesPath to
+// requests admitted by guard, as JSON or, with ?format=table, as a plain-text table.
+// Other requests receive 404 so the endpoint is not discoverable. It panics if guard is nil.
+//
+// Usage:
+//
+//	registry.RegisterWithRouter(r, db, logger, handler.WithDebugRoutes(handler.RequireDebugToken(os.Getenv("DEBUG_TOKEN"))))
+func WithDebugRoutes(guard RouteGuard) RegistrationOption {
+	if guard == nil {
+		panic("handler: WithDebugRoutes requires a guard")
+	}
+	return func(cfg *registrationConfig) {
+		cfg.debugRoutesGuard = guard
+	}
+}
+
+// DescribeRoutes returns the audit view of every served route in registration order. Routes that
+// RegisterWithRouter skips (duplicates and unsupported methods) are left out; Validate reports them.
+func (reg *Registry) DescribeRoutes() []RouteDescription {
+	routes := reg.ServedRoutes()
+	descriptions := make([]RouteDescription, len(routes))
+	for i, route := range routes {
+		descriptions[i] = describeRoute(route)
+	}
+	return descriptions
+}
+
+// describeRoute builds the RouteDescription of a route returned by ServedRoutes
+func describeRoute(route PendingRoute) RouteDescription {
+	description := RouteDescription{
+		Method:     route.Method,
+		Path:       route.Path,
+		Version:    route.RouteInfo.Version,
+		Handler:    route.HandlerName,
+		Middleware: append([]string{}, route.MiddlewareNames...),
+		Security:   route.RouteInfo.Security,
+		Deprecated: route.RouteInfo.IsDeprecated(),
+	}
+	if typed, ok := route.Handler.(typedRoute); ok {
+		params, body, response := typed.routeTypes()
+		description.Params = typeName(params)
+		description.Body = typeName(body)
+		description.Response = typeName(response)
+	}
+	for _, name := range route.MiddlewareNames {
+		if name == "RequireAuth" {
+			description.Auth = true
+		}
+	}
+	if len(description.Security) > 0 {
+		description.Auth = true
+	}
+	return description
+}
+
+// typeName returns the Go name of t, or "" for the empty struct used when a route has no params, body or response
+func typeName(t reflect.Type) string {
+	if t == nil || (t.Kind() == reflect.Struct && t.NumField() == 0 && t.Name() == "") {
+		return ""
+	}
+	return t.String()
+}
+
+// WriteRoutes writes routes as JSON or as a table. Use it to print the route table from an
+// application command, e.g. "myapp routes", or cmd/japi-routes to read it from a running server.
+//
+// Example:
+//
+//	if err := handler.WriteRoutes(os.Stdout, reg.DescribeRoutes(), handler.RouteFormatTable); err != nil {
+//	    log.Fatal(err)
+//	}
+func WriteRoutes(w io.Writer, routes []RouteDescription, format RouteFormat) error {
+	switch format {
+	case RouteFormatJSON, "":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if routes == nil {
+			routes = []RouteDescription{}
+		}
+		return encoder.Encode(routes)
+	case RouteFormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "METHOD\tPATH\tHANDLER\tPARAMS\tBODY\tRESPONSE\tAUTH\tMIDDLEWARE")
+		for _, route := range routes {
+			path := route.Path
+			if route.Version != "" {
+				path += " (" + route.Version + ")"
+			}
+			if route.Deprecated {
+				path += " [deprecated]"
+			}
+			auth := "-"
+			if route.Auth {
+				auth = "yes"
+				if len(route.Security) > 0 {
+					auth = strings.Join(route.Security, ",")
+				}
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", route.Method, path, orDash(route.Handler),
+				orDash(route.Params), orDash(route.Body), orDash(route.Response), auth, orDash(strings.Join(route.Middleware, ", ")))
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("handler: unknown route format %q", format)
+	}
+}
+
+// orDash fills empty table cells
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// debugRoutesHandler serves the route table to requests admitted by the configured guard
+func (reg *Registry) debugRoutesHandler(cfg registrationConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !cfg.debugRoutesGuard(r) {
+			http.NotFound(w, r)
+			return
+		}
+
+		format := RouteFormat(r.URL.Query().Get("format"))
+		switch format {
+		case RouteFormatTable:
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		case RouteFormatJSON, "":
+			w.Header().Set("Content-Type", "application/json")
+		default:
+			core.WriteAPIError(w, cfg.withRequestContext(r), *core.NewAPIError(http.StatusBadRequest,
+				"Unknown format", fmt.Sprintf("format %q is not json or table", format)))
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		_ = WriteRoutes(w, reg.DescribeRoutes(), format)
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import "reflect"
+
+// Merge adds the routes of other to the registry, keeping their RouteInfo, middleware names,
+// group middleware and hooks (other's registry hooks and providers keep applying to its routes only,
+// and its RequireServices checks apply when the combined registry is registered).
+// Routes added to other afterwards are not merged. Run Validate on the combined registry to
+// detect conflicts between modules.
+//
+// Example:
+//
+//	api := handler.NewRegistry()
+//	api.Merge(users.Routes())
+//	api.Merge(billing.Routes())
+func (reg *Registry) Merge(other *Registry) {
+	reg.Mount("", other)
+}
+
+// Mount is like Merge but prefixes the paths of other's routes, so a module can declare its
+// routes relative to where it is mounted. The swagger generator documents the composed paths.
+//
+// Example:
+//
+//	api := handler.NewRegistry()
+//	api.Mount("/api/v1/users", users.Routes())     // users declares GET /{id}
+//	api.Mount("/api/v1/billing", billing.Routes()) // → GET /api/v1/users/{id}, ...
+//	if err := api.Validate(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (reg *Registry) Mount(prefix string, other *Registry) {
+	routes := other.exportRoutes()
+	group := reg.Group(prefix)
+	for _, route := range routes {
+		group.register(route)
+	}
+}
+
+// exportRoutes copies the registry's routes with its registry hooks, providers and services
+// checks folded into each route
+func (reg *Registry) exportRoutes() []PendingRoute {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	providers := reg.providerSet()
+	routes := make([]PendingRoute, len(reg.routes))
+	for i, route := range reg.routes {
+		route.hooks = append(append([]Hook(nil), reg.hooks...), route.hooks...)
+		if providers != nil {
+			route.providers = append([]map[reflect.Type]provider{providers}, route.providers...)
+		}
+		route.servicesChecks = append(append([]func(services any) error(nil), reg.servicesChecks...), route.servicesChecks...)
+		route.MiddlewareNames = append([]string(nil), route.MiddlewareNames...)
+		routes[i] = route
+	}
+	return routes
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+)
+
+// Dispose releases a value created by a Provider. handlerErr is the error returned by the
+// route's handler chain (nil on success), so a transaction can commit or roll back. An error
+// returned after a successful handler becomes the handler's error unless the response has
+// already been written; then it is logged.
+type Dispose func(handlerErr error) error
+
+// Provider creates a request-scoped value of type T. It runs at most once per request, on the
+// first Resolve[T], and receives the resolving context: the request context, the authenticated
+// UserUUID/CompanyUUID once RequireAuth has run, and the route. The returned Dispose may be nil.
+// Errors that are not APIErrors are reported to the client as 500 Internal Server Error.
+type Provider[T any] func(ctx HookContext) (T, Dispose, error)
+
+// provider is a Provider with its value type erased
+type provider func(ctx HookContext) (any, Dispose, error)
+
+// Provide registers the provider of T for every route of the registry, replacing any earlier
+// provider of T. Values are created lazily by Resolve and disposed once the route's response,
+// including a streamed body, has been written.
+//
+// Example:
+//
+//	handler.Provide(reg, func(ctx handler.HookContext) (*sql.Tx, handler.Dispose, error) {
+//	    tx, err := ctx.DB.BeginTx(ctx.Context, nil)
+//	    if err != nil {
+//	        return nil, nil, err
+//	    }
+//	    return tx, func(handlerErr error) error {
+//	        if handlerErr != nil {
+//	            return tx.Rollback()
+//	        }
+//	        return tx.Commit()
+//	    }, nil
+//	})
+func Provide[T any](reg *Registry, provide Provider[T]) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if reg.providers == nil {
+		reg.providers = make(map[reflect.Type]provider)
+	}
+	reg.providers[reflect.TypeFor[T]()] = func(ctx HookContext) (any, Dispose, error) {
+		return provide(ctx)
+	}
+}
+
+// Resolve returns the request's value of type T, creating it with the registered Provider on
+// first use. It works with both HandlerContext and HookContext, and fails with a 500 APIError
+// when no provider of T is registered or the response has already been written.
+//
+// Example:
+//
+//	tx, err := handler.Resolve[*sql.Tx](ctx)
+//	if err != nil {
+//	    return UserResponse{}, err
+//	}
+func Resolve[T any](ctx requestContext) (T, error) {
+	var zero T
+	hookCtx := ctx.hookContext()
+	t := reflect.TypeFor[T]()
+
+	var scope *dependencyScope
+	if hookCtx.Context != nil {
+		scope, _ = hookCtx.Context.Value(dependencyScopeKey{}).(*dependencyScope)
+	}
+	if scope == nil {
+		return zero, dependencyError(fmt.Errorf("handler: no provider of %s for this request (register one with Provide)", t))
+	}
+
+	value, err := scope.resolve(t, hookCtx)
+	if err != nil {
+		return zero, err
+	}
+	return value.(T), nil
+}
+
+// dependencyScopeKey is the context key of the request's innermost dependencyScope
+type dependencyScopeKey struct{}
+
+// dependencyScope holds the values one registry's providers created for a request.
+// Scopes of mounted registries are nested inside the scope of the registry they are mounted in.
+type dependencyScope struct {
+	parent    *dependencyScope
+	providers map[reflect.Type]provider
+	route     RouteInfo
+
+	mu        sync.Mutex
+	values    map[reflect.Type]any
+	disposers []Dispose
+	disposed  bool
+}
+
+// resolve returns the value of t from the innermost scope that provides it
+func (s *dependencyScope) resolve(t reflect.Type, ctx HookContext) (any, error) {
+	for scope := s; scope != nil; scope = scope.parent {
+		if provide, ok := scope.providers[t]; ok {
+			return scope.get(t, provide, ctx)
+		}
+	}
+	return nil, dependencyError(fmt.Errorf("handler: no provider of %s for this request (register one with Provide)", t))
+}
+
+// get returns the cached value of t or creates it. The provider runs without holding the lock,
+// so it can resolve other values.
+func (s *dependencyScope) get(t reflect.Type, provide provider, ctx HookContext) (any, error) {
+	s.mu.Lock()
+	if s.disposed {
+		s.mu.Unlock()
+		return nil, dependencyError(fmt.Errorf("handler: %s resolved after the response was written", t))
+	}
+	if value, ok := s.values[t]; ok {
+		s.mu.Unlock()
+		return value, nil
+	}
+	s.mu.Unlock()
+
+	ctx.Route = s.route
+	value, dispose, err := provide(ctx)
+	if err != nil {
+		return nil, dependencyError(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.values[t]; ok || s.disposed {
+		// A concurrent Resolve won, or the response was written meanwhile
+		if dispose != nil {
+			_ = dispose(nil)
+		}
+		if !ok {
+			return nil, dependencyError(fmt.Errorf("handler: %s resolved after the response was written", t))
+		}
+		return existing, nil
+	}
+	if s.values == nil {
+		s.values = make(map[reflect.Type]any)
+	}
+	s.values[t] = value
+	if dispose != nil {
+		s.disposers = append(s.disposers, dispose)
+	}
+	return value, nil
+}
+
+// dispose releases the scope's values in reverse creation order. Later calls do nothing.
+func (s *dependencyScope) dispose(handlerErr error) error {
+	s.mu.Lock()
+	if s.disposed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.disposed = true
+	disposers := s.disposers
+	s.disposers = nil
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(disposers) - 1; i >= 0; i-- {
+		if err := disposers[i](handlerErr); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// disposeUntil disposes the scope and the scopes it is nested in, up to but excluding outer
+func (s *dependencyScope) disposeUntil(outer *dependencyScope, handlerErr error) error {
+	var errs []error
+	for scope := s; scope != outer; scope = scope.parent {
+		if err := scope.dispose(handlerErr); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// dependencyError passes APIErrors through and hides other errors behind a 500
+func dependencyError(err error) error {
+	if _, ok := core.AsAPIError(err); ok {
+		return err
+	}
+	return core.Wrap(err, http.StatusInternalServerError, "Internal Server Error")
+}
+
+// errHandlerPanicked is passed to Dispose when the handler chain panics
+var errHandlerPanicked = errors.New("handler: panicked")
+
+// routeDependencies are the providers of a route, one set per registry it was registered in or
+// mounted from, outermost first
+type routeDependencies struct {
+	providers []map[reflect.Type]provider
+	route     RouteInfo
+}
+
+// open nests a dependency scope for every provider set inside the request's scope, if any.
+// It returns the innermost new scope and the request's scope.
+func (d *routeDependencies) open(r *http.Request) (scope, outer *dependencyScope, _ *http.Request) {
+	outer, _ = r.Context().Value(dependencyScopeKey{}).(*dependencyScope)
+	scope = outer
+	for _, providers := range d.providers {
+		scope = &dependencyScope{parent: scope, providers: providers, route: d.route}
+	}
+	return scope, outer, r.WithContext(context.WithValue(r.Context(), dependencyScopeKey{}, scope))
+}
+
+// serveWithDependencies runs serve, which writes the whole response including response middleware,
+// inside the route's dependency scopes and disposes the values created in them afterwards, so
+// streamed bodies can still read through them. A Dispose error after a successful handler becomes
+// the handler's error if nothing was written yet, and is logged otherwise.
+func serveWithDependencies(
+	deps *routeDependencies,
+	logger *slog.Logger,
+	w http.ResponseWriter,
+	r *http.Request,
+	serve func(w http.ResponseWriter, r *http.Request) error,
+) error {
+	scope, outer, r := deps.open(r)
+	dw := &dependencyWriter{ResponseWriter: w}
+
+	completed := false
+	defer func() {
+		// Roll back when a panic unwinds through the chain; the adapter recovers it
+		if !completed {
+			_ = scope.disposeUntil(outer, errHandlerPanicked)
+		}
+	}()
+	err := serve(dw, r)
+	completed = true
+
+	disposeErr := scope.disposeUntil(outer, err)
+	if disposeErr == nil {
+		return err
+	}
+	if err != nil || dw.wroteHeader {
+		logger.Error("Failed to dispose request dependencies", "path", deps.route.Path, "error", disposeErr)
+		return err
+	}
+	return dependencyError(disposeErr)
+}
+
+// dependencyWriter records whether the response has been committed
+type dependencyWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (dw *dependencyWriter) WriteHeader(status int) {
+	dw.wroteHeader = true
+	dw.ResponseWriter.WriteHeader(status)
+}
+
+func (dw *dependencyWriter) Write(b []byte) (int, error) {
+	dw.wroteHeader = true
+	return dw.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController (flushing, deadlines)
+func (dw *dependencyWriter) Unwrap() http.ResponseWriter {
+	return dw.ResponseWriter
+}
+
+// providerSet returns a copy of the registry's providers, or nil without any.
+// The caller holds reg.mu.
+func (reg *Registry) providerSet() map[reflect.Type]provider {
+	if len(reg.providers) == 0 {
+		return nil
+	}
+	providers := make(map[reflect.Type]provider, len(reg.providers))
+	for t, provide := range reg.providers {
+		providers[t] = provide
+	}
+	return providers
+}
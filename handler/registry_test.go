@@ -1,14 +1,20 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/platform-smith-labs/japi-core/v3/core"
 )
 
 // TestNewRegistry verifies registry creation
@@ -410,3 +416,674 @@ func BenchmarkConcurrentRegistration(b *testing.B) {
 		}
 	})
 }
+
+// TestRegisterWithRouterCacheControl verifies route Cache-Control policies apply to successful responses only
+func TestRegisterWithRouterCacheControl(t *testing.T) {
+	reg := NewRegistry()
+	MakeHandler(reg,
+		RouteInfo{Method: "GET", Path: "/ok", CacheControl: "private, max-age=60"},
+		func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			w.WriteHeader(http.StatusOK)
+			return struct{}{}, nil
+		},
+	)
+	MakeHandler(reg,
+		RouteInfo{Method: "GET", Path: "/fail", CacheControl: "private, max-age=60"},
+		func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, io.ErrUnexpectedEOF
+		},
+	)
+
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/ok", nil))
+	if got := rec.Header().Get("Cache-Control"); got != "private, max-age=60" {
+		t.Errorf("Expected route policy on success, got %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/fail", nil))
+	if got := rec.Header().Get("Cache-Control"); got != "" {
+		t.Errorf("Expected no Cache-Control on error, got %q", got)
+	}
+}
+
+// TestRegistryGroup verifies groups compose prefixes, defaults and middleware, and nest
+func TestRegistryGroup(t *testing.T) {
+	var calls []string
+	tracing := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	ok := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		w.WriteHeader(http.StatusOK)
+		return struct{}{}, nil
+	}
+
+	reg := NewRegistry()
+	v1 := reg.Group("/api/v1/", WithTags("Users"), WithSecurity("BearerAuth"), WithMiddleware(tracing("v1")))
+	admin := v1.Group("/admin", WithTags("Admin"), WithMiddleware(tracing("admin")))
+
+	MakeHandler(v1, RouteInfo{Method: "GET", Path: "/users"}, ok)
+	MakeHandler(admin, RouteInfo{Method: "DELETE", Path: "/users/{id}", Tags: []string{"Danger"}}, ok)
+
+	routes := reg.GetRoutes()
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %d", len(routes))
+	}
+	if routes[0].Path != "/api/v1/users" || routes[0].RouteInfo.Path != "/api/v1/users" {
+		t.Errorf("Expected /api/v1/users, got %s (%s)", routes[0].Path, routes[0].RouteInfo.Path)
+	}
+	if routes[1].Path != "/api/v1/admin/users/{id}" {
+		t.Errorf("Expected /api/v1/admin/users/{id}, got %s", routes[1].Path)
+	}
+	if len(routes[0].RouteInfo.Tags) != 1 || routes[0].RouteInfo.Tags[0] != "Users" {
+		t.Errorf("Expected default tag Users, got %v", routes[0].RouteInfo.Tags)
+	}
+	if len(routes[1].RouteInfo.Tags) != 1 || routes[1].RouteInfo.Tags[0] != "Danger" {
+		t.Errorf("Expected route tags to win, got %v", routes[1].RouteInfo.Tags)
+	}
+	if len(routes[1].RouteInfo.Security) != 1 || routes[1].RouteInfo.Security[0] != "BearerAuth" {
+		t.Errorf("Expected inherited security, got %v", routes[1].RouteInfo.Security)
+	}
+	if len(routes[1].MiddlewareNames) != 2 {
+		t.Errorf("Expected group middleware names to be recorded, got %v", routes[1].MiddlewareNames)
+	}
+
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/v1/admin/users/42", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if len(calls) != 2 || calls[0] != "v1" || calls[1] != "admin" {
+		t.Errorf("Expected outer group middleware to run first, got %v", calls)
+	}
+
+	calls = nil
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/users", nil))
+	if rec.Code != http.StatusOK || len(calls) != 1 {
+		t.Errorf("Expected only v1 middleware on /api/v1/users, got %d %v", rec.Code, calls)
+	}
+}
+
+// TestRegistryUse verifies registry and group hooks wrap every route and can set context fields
+func TestRegistryUse(t *testing.T) {
+	var calls []string
+	userID := uuid.New()
+	authenticate := func(next HookHandler) HookHandler {
+		return func(ctx HookContext, w http.ResponseWriter, r *http.Request) (any, error) {
+			calls = append(calls, "authenticate "+ctx.Route.Path)
+			ctx.UserUUID = NewNullable(userID)
+			return next(ctx, w, r)
+		}
+	}
+	audit := func(next HookHandler) HookHandler {
+		return func(ctx HookContext, w http.ResponseWriter, r *http.Request) (any, error) {
+			calls = append(calls, "audit")
+			response, err := next(ctx, w, r)
+			if response != "pong" {
+				t.Errorf("Expected the route response in the hook, got %v", response)
+			}
+			return response, err
+		}
+	}
+
+	reg := NewRegistry()
+	MakeHandler(reg.Group("/admin", WithHooks(audit)), RouteInfo{Method: "GET", Path: "/ping"},
+		func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (string, error) {
+			if got, ok := ctx.UserUUID.TryValue(); !ok || got != userID {
+				t.Errorf("Expected the hook's UserUUID, got %v", got)
+			}
+			w.WriteHeader(http.StatusOK)
+			return "pong", nil
+		},
+	)
+	reg.Use(authenticate) // applies to routes registered earlier
+
+	routes := reg.GetRoutes()
+	if names := routes[0].MiddlewareNames; len(names) != 2 {
+		t.Errorf("Expected hook names to be recorded, got %v", names)
+	}
+
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/admin/ping", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if len(calls) != 2 || calls[0] != "authenticate /admin/ping" || calls[1] != "audit" {
+		t.Errorf("Expected registry hooks before group hooks, got %v", calls)
+	}
+}
+
+// TestRegistryValidate verifies route table mistakes are reported
+func TestRegistryValidate(t *testing.T) {
+	type userParams struct {
+		ID    string `param:"id"`
+		OrgID string `param:"org"`
+		Limit int    `query:"limit"`
+	}
+	noop := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		return struct{}{}, nil
+	}
+
+	t.Run("valid table", func(t *testing.T) {
+		reg := NewRegistry()
+		MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users/{org}/{id:[0-9]+}"},
+			func(ctx HandlerContext[userParams, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+				return struct{}{}, nil
+			})
+		MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users/"}, noop)
+		if err := reg.Validate(); err != nil {
+			t.Errorf("Expected no issues, got %v", err)
+		}
+	})
+
+	t.Run("reports every issue", func(t *testing.T) {
+		reg := NewRegistry()
+		MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users/{userID}"}, noop)
+		MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users/{id}"},
+			func(ctx HandlerContext[userParams, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+				return struct{}{}, nil
+			})
+		MakeHandler(reg, RouteInfo{Method: "FETCH", Path: "/things"}, noop)
+		reg.register(PendingRoute{
+			Method:          "DELETE",
+			Path:            "/things",
+			Handler:         TypedHandler[struct{}, struct{}, struct{}]{handler: noop},
+			RouteInfo:       RouteInfo{Method: "DELETE", Path: "/things"},
+			MiddlewareNames: []string{"ParseBody"},
+		})
+
+		err := reg.Validate()
+		var validationErr *RouteValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected *RouteValidationError, got %v", err)
+		}
+
+		kinds := make(map[RouteIssueKind]int)
+		for _, issue := range validationErr.Issues {
+			kinds[issue.Kind]++
+		}
+		expected := map[RouteIssueKind]int{
+			IssueDuplicateRoute:   1, // /users/{id} conflicts with /users/{userID}
+			IssueUnknownPathParam: 1, // param:"org" has no {org}
+			IssueUnknownMethod:    1,
+			IssueBodyNotAllowed:   1,
+		}
+		for kind, count := range expected {
+			if kinds[kind] != count {
+				t.Errorf("Expected %d %s issues, got %d (%v)", count, kind, kinds[kind], validationErr.Issues)
+			}
+		}
+		if len(validationErr.Issues) != 4 {
+			t.Errorf("Expected 4 issues, got %v", validationErr.Issues)
+		}
+	})
+}
+
+// TestRegisterWithRouterSkipsInvalidRoutes verifies duplicates and unknown methods are not registered
+func TestRegisterWithRouterSkipsInvalidRoutes(t *testing.T) {
+	respond := func(name string) func(HandlerContext[struct{}, struct{}], http.ResponseWriter, *http.Request) (struct{}, error) {
+		return func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			w.Header().Set("X-Handler", name)
+			return struct{}{}, nil
+		}
+	}
+
+	reg := NewRegistry()
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users/{userID}"}, respond("first"))
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users/{id}"}, respond("second"))
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users/{key}"}, respond("third"))
+	MakeHandler(reg, RouteInfo{Method: "FETCH", Path: "/things"}, respond("fetch"))
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/things"}, respond("things"))
+
+	r := chi.NewRouter()
+	err := reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var validationErr *RouteValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *RouteValidationError, got %v", err)
+	}
+	if len(validationErr.Issues) != 3 {
+		t.Errorf("Expected 3 issues, got %v", validationErr.Issues)
+	}
+	for _, issue := range validationErr.Issues {
+		if issue.Kind == IssueDuplicateRoute && issue.Message != "conflicts with GET /users/{userID}" {
+			t.Errorf("Expected duplicates to name the first registration, got %q", issue.Message)
+		}
+	}
+	if served := reg.ServedRoutes(); len(served) != 2 || served[0].Path != "/users/{userID}" || served[1].Path != "/things" {
+		t.Errorf("Expected the served routes without skipped ones, got %v", served)
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/users/42", nil))
+	if got := rec.Header().Get("X-Handler"); got != "first" {
+		t.Errorf("Expected the first registration to serve the route, got %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/things", nil))
+	if got := rec.Header().Get("X-Handler"); got != "things" {
+		t.Errorf("Expected valid routes to be registered, got %q", got)
+	}
+
+	valid := NewRegistry()
+	MakeHandler(valid, RouteInfo{Method: "GET", Path: "/things"}, respond("things"))
+	if err := valid.RegisterWithRouter(chi.NewRouter(), nil, slog.New(slog.NewTextHandler(io.Discard, nil))); err != nil {
+		t.Errorf("Expected no error for a valid table, got %v", err)
+	}
+}
+
+// TestRegistryMount verifies module registries are combined with prefixes, metadata and hooks
+func TestRegistryMount(t *testing.T) {
+	var hookCalls int
+	counting := func(next HookHandler) HookHandler {
+		return func(ctx HookContext, w http.ResponseWriter, r *http.Request) (any, error) {
+			hookCalls++
+			return next(ctx, w, r)
+		}
+	}
+	ok := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		w.WriteHeader(http.StatusOK)
+		return struct{}{}, nil
+	}
+
+	users := NewRegistry()
+	users.Use(counting)
+	MakeHandler(users, RouteInfo{Method: "GET", Path: "/{id}", Tags: []string{"Users"}}, ok)
+
+	billing := NewRegistry()
+	MakeHandler(billing, RouteInfo{Method: "GET", Path: "/api/v1/invoices"}, ok)
+
+	api := NewRegistry()
+	api.Mount("/api/v1/users", users)
+	api.Merge(billing)
+
+	routes := api.GetRoutes()
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %d", len(routes))
+	}
+	if routes[0].Path != "/api/v1/users/{id}" || routes[0].RouteInfo.Tags[0] != "Users" {
+		t.Errorf("Expected mounted path with its metadata, got %s %v", routes[0].Path, routes[0].RouteInfo.Tags)
+	}
+	if len(routes[0].MiddlewareNames) != 1 {
+		t.Errorf("Expected the module hook name to be kept, got %v", routes[0].MiddlewareNames)
+	}
+
+	if err := api.Validate(); err != nil {
+		t.Errorf("Expected no issues, got %v", err)
+	}
+	conflicting := NewRegistry()
+	conflicting.Mount("/api/v1/users", users)
+	MakeHandler(conflicting, RouteInfo{Method: "GET", Path: "/api/v1/users/{userID}"}, ok)
+	var validationErr *RouteValidationError
+	if err := conflicting.Validate(); !errors.As(err, &validationErr) || validationErr.Issues[0].Kind != IssueDuplicateRoute {
+		t.Errorf("Expected a cross-module duplicate route, got %v", err)
+	}
+
+	r := chi.NewRouter()
+	api.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/users/7", nil))
+	if rec.Code != http.StatusOK || hookCalls != 1 {
+		t.Errorf("Expected the module hook to run once, got status %d, %d calls", rec.Code, hookCalls)
+	}
+}
+
+type deprecationRecorder struct {
+	calls []string
+}
+
+func (d *deprecationRecorder) ObserveDeprecatedCall(method, path, client string) {
+	d.calls = append(d.calls, method+" "+path+" "+client)
+}
+
+// TestRegisterWithRouterDeprecation verifies lifecycle headers and usage reporting on deprecated routes
+func TestRegisterWithRouterDeprecation(t *testing.T) {
+	ok := func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		w.WriteHeader(http.StatusOK)
+		return struct{}{}, nil
+	}
+	reg := NewRegistry()
+	MakeHandler(reg, RouteInfo{
+		Method:       "GET",
+		Path:         "/v1/users",
+		DeprecatedAt: time.Unix(1735689600, 0),
+		Sunset:       time.Date(2026, time.June, 30, 0, 0, 0, 0, time.UTC),
+		Successor:    "/v2/users",
+	}, ok)
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/v2/users"}, ok)
+
+	observer := &deprecationRecorder{}
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), WithDeprecationObserver(observer, "billing-service"))
+
+	req := httptest.NewRequest("GET", "/v1/users", nil)
+	req.Header.Set("User-Agent", "billing-service/2.3 (+https://example.com)")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Deprecation"); got != "@1735689600" {
+		t.Errorf("Expected Deprecation @1735689600, got %q", got)
+	}
+	if got := rec.Header().Get("Sunset"); got != "Tue, 30 Jun 2026 00:00:00 GMT" {
+		t.Errorf("Expected Sunset date, got %q", got)
+	}
+	if got := rec.Header().Get("Link"); got != `</v2/users>; rel="successor-version"` {
+		t.Errorf("Expected successor link, got %q", got)
+	}
+	if len(observer.calls) != 1 || observer.calls[0] != "GET /v1/users billing-service" {
+		t.Errorf("Expected the call to be reported, got %v", observer.calls)
+	}
+
+	// Unknown and missing User-Agents share one label
+	for _, userAgent := range []string{"scanner-8f3a1c/1.0", ""} {
+		req = httptest.NewRequest("GET", "/v1/users", nil)
+		req.Header.Set("User-Agent", userAgent)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if len(observer.calls) != 3 || observer.calls[1] != "GET /v1/users other" || observer.calls[2] != "GET /v1/users other" {
+		t.Errorf("Expected unknown clients to be reported as other, got %v", observer.calls)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/v2/users", nil))
+	if rec.Header().Get("Deprecation") != "" || len(observer.calls) != 3 {
+		t.Errorf("Expected no lifecycle headers on current routes")
+	}
+}
+
+// TestRegisterWithRouterVersioning verifies versions of one route are selected per request
+func TestRegisterWithRouterVersioning(t *testing.T) {
+	version := func(name string) Handler[struct{}, struct{}, struct{}] {
+		return func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			w.Header().Set("X-Served-By", name+" "+r.Header.Get("Accept"))
+			w.WriteHeader(http.StatusOK)
+			return struct{}{}, nil
+		}
+	}
+	reg := NewRegistry()
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users", Version: "v1"}, version("v1"))
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users", Version: "v2"}, version("v2"))
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users", Version: "v3"}, version("v3"))
+	if err := reg.Validate(); err != nil {
+		t.Fatalf("Expected versions not to conflict, got %v", err)
+	}
+
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithVersioning(VersioningConfig{Vendor: "acme", Default: "v2"}))
+
+	tests := []struct {
+		name     string
+		header   string
+		accept   string
+		status   int
+		servedBy string
+	}{
+		{"default version", "", "", http.StatusOK, "v2 "},
+		{"version header", "1", "", http.StatusOK, "v1 "},
+		{"vendor media type", "", "application/vnd.acme.v3+json", http.StatusOK, "v3 application/json"},
+		{"other vendor ignored", "", "application/vnd.other.v3+json", http.StatusOK, "v2 application/vnd.other.v3+json"},
+		{"unknown version", "v9", "", http.StatusNotAcceptable, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/users", nil)
+			if tt.header != "" {
+				req.Header.Set(DefaultVersionHeader, tt.header)
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("Expected %d, got %d", tt.status, rec.Code)
+			}
+			if got := rec.Header().Get("X-Served-By"); got != tt.servedBy {
+				t.Errorf("Expected %q, got %q", tt.servedBy, got)
+			}
+		})
+	}
+}
+
+// TestProvide verifies request-scoped values are created lazily, once, and disposed with the handler's error
+func TestProvide(t *testing.T) {
+	type tenantRepo struct{ company uuid.UUID }
+	type requestCache struct{ id int }
+
+	companyID := uuid.New()
+	var created, disposed []string
+	var commitErr error
+
+	reg := NewRegistry()
+	reg.Use(func(next HookHandler) HookHandler { // stands in for RequireAuth
+		return func(ctx HookContext, w http.ResponseWriter, r *http.Request) (any, error) {
+			ctx.CompanyUUID = NewNullable(companyID)
+			return next(ctx, w, r)
+		}
+	})
+	Provide(reg, func(ctx HookContext) (*tenantRepo, Dispose, error) {
+		created = append(created, "repo "+ctx.Route.Path)
+		return &tenantRepo{company: ctx.CompanyUUID.ValueOrDefault()}, func(handlerErr error) error {
+			disposed = append(disposed, "repo")
+			if handlerErr != nil {
+				return nil
+			}
+			return commitErr
+		}, nil
+	})
+	Provide(reg, func(ctx HookContext) (*requestCache, Dispose, error) {
+		if _, err := Resolve[*tenantRepo](ctx); err != nil {
+			return nil, nil, err
+		}
+		created = append(created, "cache")
+		return &requestCache{id: len(created)}, func(error) error {
+			disposed = append(disposed, "cache")
+			return nil
+		}, nil
+	})
+	Provide(reg, func(ctx HookContext) (string, Dispose, error) {
+		return "", nil, core.NewAPIError(http.StatusForbidden, "Tenant suspended")
+	})
+
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/reports"},
+		func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			cache, err := Resolve[*requestCache](ctx)
+			if err != nil {
+				return struct{}{}, err
+			}
+			again, _ := Resolve[*requestCache](ctx)
+			repo, _ := Resolve[*tenantRepo](ctx)
+			if again != cache || repo.company != companyID {
+				t.Errorf("Expected one value per request with the company, got %v %v", again, repo)
+			}
+			return struct{}{}, nil
+		},
+		respondOK[struct{}, struct{}, struct{}],
+	)
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/unused"},
+		func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, nil
+		},
+		respondOK[struct{}, struct{}, struct{}],
+	)
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/commit"},
+		func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			_, err := Resolve[*tenantRepo](ctx)
+			return struct{}{}, err
+		},
+	)
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/suspended"},
+		func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			_, err := Resolve[string](ctx)
+			return struct{}{}, err
+		},
+		respondOK[struct{}, struct{}, struct{}],
+	)
+
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	serve := func(path string) int {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec.Code
+	}
+
+	if code := serve("/reports"); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if len(created) != 2 || created[0] != "repo /reports" || created[1] != "cache" {
+		t.Errorf("Expected each value to be created once, got %v", created)
+	}
+	if len(disposed) != 2 || disposed[0] != "cache" || disposed[1] != "repo" {
+		t.Errorf("Expected disposal in reverse creation order, got %v", disposed)
+	}
+
+	created, disposed = nil, nil
+	if code := serve("/unused"); code != http.StatusOK || len(created) != 0 {
+		t.Errorf("Expected no values for a route that resolves none, got %d %v", code, created)
+	}
+
+	commitErr = errors.New("commit failed")
+	if code := serve("/commit"); code != http.StatusInternalServerError {
+		t.Errorf("Expected a failed disposal to become a 500, got %d", code)
+	}
+	if code := serve("/reports"); code != http.StatusOK {
+		t.Errorf("Expected a failed disposal after the response was written to be logged, got %d", code)
+	}
+	if code := serve("/suspended"); code != http.StatusForbidden {
+		t.Errorf("Expected the provider's APIError, got %d", code)
+	}
+
+	// Providers of a mounted registry apply to its routes, and the parent's remain available
+	module := NewRegistry()
+	Provide(module, func(ctx HookContext) (string, Dispose, error) {
+		return "module", nil, nil
+	})
+	MakeHandler(module, RouteInfo{Method: "GET", Path: "/name"},
+		func(ctx HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (string, error) {
+			name, err := Resolve[string](ctx)
+			if err != nil {
+				return "", err
+			}
+			if _, err := Resolve[*tenantRepo](ctx); err != nil {
+				return "", err
+			}
+			w.Header().Set("X-Name", name)
+			return name, nil
+		},
+		respondOK[struct{}, struct{}, string],
+	)
+	reg.Mount("/module", module)
+	r = chi.NewRouter()
+	commitErr = nil
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/module/name", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("X-Name") != "module" {
+		t.Errorf("Expected the module's provider, got %d %q", rec.Code, rec.Header().Get("X-Name"))
+	}
+
+	// Outside a registered route there is no provider
+	if _, err := Resolve[string](HookContext{}); err == nil {
+		t.Error("Expected an error without providers")
+	}
+}
+
+// respondOK writes 200 after a successful handler, standing in for response middleware
+func respondOK[ParamTypeT any, BodyTypeT any, ResponseBodyT any](next Handler[ParamTypeT, BodyTypeT, ResponseBodyT]) Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+	return func(ctx HandlerContext[ParamTypeT, BodyTypeT], w http.ResponseWriter, r *http.Request) (ResponseBodyT, error) {
+		response, err := next(ctx, w, r)
+		if err == nil {
+			w.WriteHeader(http.StatusOK)
+		}
+		return response, err
+	}
+}
+
+// getInvoice is a named handler so its name can be introspected
+func getInvoice(ctx HandlerContext[invoiceParams, struct{}], w http.ResponseWriter, r *http.Request) ([]string, error) {
+	return nil, nil
+}
+
+type invoiceParams struct {
+	ID string `param:"id"`
+}
+
+// TestDebugRoutes verifies the route table is described and served behind its guard
+func TestDebugRoutes(t *testing.T) {
+	reg := NewRegistry()
+	MakeHandler(reg.Group("/api", WithSecurity("BearerAuth")), RouteInfo{Method: "GET", Path: "/invoices/{id}", Version: "v2"},
+		getInvoice, respondOK[invoiceParams, struct{}, []string])
+	// Skipped by RegisterWithRouter, so not described
+	MakeHandler(reg, RouteInfo{Method: "GET", Path: "/api/invoices/{invoiceID}", Version: "v2"}, getInvoice)
+	MakeHandler(reg, RouteInfo{Method: "FETCH", Path: "/api/invoices"}, getInvoice)
+
+	routes := reg.DescribeRoutes()
+	if len(routes) != 1 {
+		t.Fatalf("Expected 1 route, got %d", len(routes))
+	}
+	route := routes[0]
+	if route.Handler != "getInvoice" || route.Params != "handler.invoiceParams" || route.Body != "" || route.Response != "[]string" {
+		t.Errorf("Expected handler and type names, got %+v", route)
+	}
+	if !route.Auth || len(route.Middleware) != 1 || route.Middleware[0] != "respondOK" {
+		t.Errorf("Expected auth and middleware, got %+v", route)
+	}
+
+	var table strings.Builder
+	if err := WriteRoutes(&table, routes, RouteFormatTable); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "METHOD") ||
+		!strings.Contains(lines[1], "/api/invoices/{id} (v2)") || !strings.Contains(lines[1], "BearerAuth") {
+		t.Errorf("Unexpected table:\n%s", table.String())
+	}
+	if err := WriteRoutes(io.Discard, routes, "yaml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), WithDebugRoutes(RequireDebugToken("secret")))
+	get := func(target, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get(DebugRoutesPath, "wrong"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a rejected request, got %d", rec.Code)
+	}
+	rec := get(DebugRoutesPath, "secret")
+	var served []RouteDescription
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil || len(served) != 1 || served[0].Path != "/api/invoices/{id}" {
+		t.Errorf("Expected the JSON route table, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := get(DebugRoutesPath+"?format=table", "secret"); !strings.HasPrefix(rec.Body.String(), "METHOD") {
+		t.Errorf("Expected the table, got %s", rec.Body.String())
+	}
+	if rec := get(DebugRoutesPath+"?format=xml", "secret"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown format, got %d", rec.Code)
+	}
+}
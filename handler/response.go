@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+)
+
+// Response wraps a handler's response body with an explicit status code, headers and cookies.
+// Return it from a handler (Handler[P, B, Response[T]]) when the method-based default status
+// (201 for POST, 200 otherwise) is not right, e.g. 202 Accepted or 201 with a Location header.
+// Response middleware (typed.ResponseJSON, ResponseNegotiated, ...) write Body and apply the rest.
+//
+// Declare the status codes in RouteInfo.StatusCodes so the swagger generator documents them.
+//
+// Example:
+//
+//	func CreateUser(ctx HandlerContext[struct{}, CreateUserBody], w http.ResponseWriter, r *http.Request) (Response[User], error) {
+//	    user, err := insertUser(ctx)
+//	    if err != nil {
+//	        return Response[User]{}, err
+//	    }
+//	    return Created(user, "/api/v1/users/"+user.ID.String()), nil
+//	}
+type Response[T any] struct {
+	Status  int            // HTTP status; 0 uses the method default
+	Headers http.Header    // Additional response headers
+	Cookies []*http.Cookie // Cookies to set
+	Body    T              // Encoded by the response middleware; omitted for 204 and 304
+}
+
+// ResponseMetadata is implemented by Response so response middleware can apply its
+// status, headers and cookies without knowing its body type
+type ResponseMetadata interface {
+	ResponseStatus() int
+	ResponseHeaders() http.Header
+	ResponseCookies() []*http.Cookie
+	ResponseBody() any
+}
+
+// NewResponse wraps body with the method default status
+func NewResponse[T any](body T) Response[T] {
+	return Response[T]{Body: body}
+}
+
+// OK wraps body as 200 OK
+func OK[T any](body T) Response[T] {
+	return Response[T]{Status: http.StatusOK, Body: body}
+}
+
+// Created wraps body as 201 Created, with a Location header when location is not empty
+func Created[T any](body T, location string) Response[T] {
+	response := Response[T]{Status: http.StatusCreated, Body: body}
+	if location != "" {
+		response = response.WithHeader("Location", location)
+	}
+	return response
+}
+
+// Accepted wraps body as 202 Accepted, for work that completes asynchronously
+func Accepted[T any](body T) Response[T] {
+	return Response[T]{Status: http.StatusAccepted, Body: body}
+}
+
+// NoContent returns a 204 No Content response
+func NoContent[T any]() Response[T] {
+	return Response[T]{Status: http.StatusNoContent}
+}
+
+// WithStatus returns a copy of the response with the given status
+func (resp Response[T]) WithStatus(status int) Response[T] {
+	resp.Status = status
+	return resp
+}
+
+// WithHeader returns a copy of the response that adds a header value
+func (resp Response[T]) WithHeader(key, value string) Response[T] {
+	headers := resp.Headers.Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	headers.Add(key, value)
+	resp.Headers = headers
+	return resp
+}
+
+// WithCookie returns a copy of the response that sets cookie
+func (resp Response[T]) WithCookie(cookie *http.Cookie) Response[T] {
+	resp.Cookies = append(append([]*http.Cookie(nil), resp.Cookies...), cookie)
+	return resp
+}
+
+// ResponseStatus returns the explicit status, or 0 for the method default
+func (resp Response[T]) ResponseStatus() int {
+	return resp.Status
+}
+
+// ResponseHeaders returns the additional response headers
+func (resp Response[T]) ResponseHeaders() http.Header {
+	return resp.Headers
+}
+
+// ResponseCookies returns the cookies to set
+func (resp Response[T]) ResponseCookies() []*http.Cookie {
+	return resp.Cookies
+}
+
+// ResponseBody returns the body to encode
+func (resp Response[T]) ResponseBody() any {
+	return resp.Body
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// requestContext is implemented by HandlerContext and HookContext
+type requestContext interface {
+	hookContext() HookContext
+}
+
+// hookContext returns the non-generic fields of the context; Route is left empty
+func (ctx HandlerContext[ParamTypeT, BodyTypeT]) hookContext() HookContext {
+	return HookContext{
+		Context:     ctx.Context,
+		DB:          ctx.DB,
+		Logger:      ctx.Logger,
+		Services:    ctx.Services,
+		RequestID:   ctx.RequestID,
+		UserUUID:    ctx.UserUUID,
+		CompanyUUID: ctx.CompanyUUID,
+	}
+}
+
+func (ctx HookContext) hookContext() HookContext {
+	return ctx
+}
+
+// Services returns the application services registered with WithServices as T.
+// It works with both HandlerContext and HookContext. A mismatch is a configuration error,
+// so it panics (recovered as a 500); declare T with RequireServices so RegisterWithRouter
+// reports it at startup instead.
+//
+// Example:
+//
+//	svc := handler.Services[*AppServices](ctx)
+//	resp, err := svc.OrchClient.Get(ctx.Context, "/api/v1/tasks")
+func Services[T any](ctx requestContext) T {
+	services, err := servicesAs[T](ctx.hookContext().Services)
+	if err != nil {
+		panic(err)
+	}
+	return services
+}
+
+// LookupServices is like Services but reports a mismatch instead of panicking
+func LookupServices[T any](ctx requestContext) (T, bool) {
+	services, err := servicesAs[T](ctx.hookContext().Services)
+	return services, err == nil
+}
+
+// RequireServices declares that the registry's handlers read their services as T. Every
+// RegisterWithRouter of the registry, or of a registry it is mounted in, then returns an error
+// without registering any route unless the services registered with WithServices are a T, so a
+// misconfigured application fails at startup instead of on the first call to Services.
+//
+// Usage:
+//
+//	var Server = handler.NewRegistry()
+//
+//	func init() {
+//	    handler.RequireServices[*AppServices](Server)
+//	}
+func RequireServices[T any](reg *Registry) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.servicesChecks = append(reg.servicesChecks, func(services any) error {
+		_, err := servicesAs[T](services)
+		return err
+	})
+}
+
+// checkServices runs the services checks of the registry and of the routes mounted into it.
+// The caller holds reg.mu.
+func (reg *Registry) checkServices(services any) error {
+	for _, check := range reg.servicesChecks {
+		if err := check(services); err != nil {
+			return err
+		}
+	}
+	for _, route := range reg.routes {
+		for _, check := range route.servicesChecks {
+			if err := check(services); err != nil {
+				return fmt.Errorf("%w (required by %s %s)", err, route.Method, route.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// servicesAs converts services to T, describing the mismatch if it is not one
+func servicesAs[T any](services any) (T, error) {
+	typed, ok := services.(T)
+	if !ok {
+		if services == nil {
+			return typed, fmt.Errorf("handler: no services registered, expected %s (use WithServices)", reflect.TypeFor[T]())
+		}
+		return typed, fmt.Errorf("handler: services are %T, expected %s", services, reflect.TypeFor[T]())
+	}
+	return typed, nil
+}
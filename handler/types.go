@@ -10,9 +10,11 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/platform-smith-labs/japi-core/v3/core"
 )
 
 // HandlerContext contains application dependencies and request-scoped data
@@ -26,7 +28,7 @@ type HandlerContext[ParamTypeT any, BodyTypeT any] struct {
 	// Application dependencies
 	DB       *sql.DB
 	Logger   *slog.Logger
-	Services any // Application-defined dependencies (set via WithServices option)
+	Services any // Application-defined dependencies (set via WithServices option; read with Services[T])
 
 	// Request-scoped data
 	Params    Nullable[ParamTypeT] // Optional parameters from URL/query
@@ -53,6 +55,22 @@ type RouteInfo struct {
 	Summary     string   // Optional: Brief description for Swagger (auto-generated if empty)
 	Description string   // Optional: Detailed description for Swagger (auto-generated if empty)
 	Tags        []string // Optional: Tags for grouping in Swagger UI
+	ErrorCodes  []string // Optional: Error codes (from the error catalog) this route can return
+	Produces    []string // Optional: Response media types for Swagger (inferred from response middleware if empty)
+	StatusCodes []int    // Optional: Success status codes returned via handler.Response, documented instead of 200
+	Security    []string // Optional: Swagger security schemes, e.g. "BearerAuth" (implied by RequireAuth middleware)
+	Version     string   // Optional: API version the route belongs to, e.g. "v1" (x-api-version in Swagger)
+
+	// Lifecycle: deprecated routes send Deprecation, Sunset and Link: rel="successor-version"
+	// headers and are marked deprecated in Swagger (see WithDeprecationObserver for usage tracking)
+	Deprecated   bool      // The route is deprecated
+	DeprecatedAt time.Time // Optional: When the route was deprecated (implies Deprecated)
+	Sunset       time.Time // Optional: When the route will stop responding
+	Successor    string    // Optional: Link to the replacement route or its documentation
+
+	// CacheControl is sent as the Cache-Control header on successful (2xx/304) responses,
+	// e.g. "private, max-age=60" or "no-store". Empty leaves the header unset.
+	CacheControl string
 }
 
 // AdaptableHandler interface knows how to create an adapted http.HandlerFunc
@@ -63,6 +81,10 @@ type AdaptableHandler interface {
 // TypedHandler wraps any Handler type and implements AdaptableHandler
 type TypedHandler[ParamTypeT any, BodyTypeT any, ResponseBodyT any] struct {
 	handler Handler[ParamTypeT, BodyTypeT, ResponseBodyT]
+
+	// The parts of handler, kept so hooks can be wrapped around the base handler
+	base       Handler[ParamTypeT, BodyTypeT, ResponseBodyT]
+	middleware []Middleware[ParamTypeT, BodyTypeT, ResponseBodyT]
 }
 
 // Adapt converts the typed handler to http.HandlerFunc using AdaptHandler
@@ -75,6 +97,11 @@ func (th TypedHandler[ParamTypeT, BodyTypeT, ResponseBodyT]) AdaptWithServices(d
 	return AdaptHandlerWithServices(database, logger, services, th.handler)
 }
 
+// adaptWithConfig converts the typed handler to http.HandlerFunc using the full registration config.
+func (th TypedHandler[ParamTypeT, BodyTypeT, ResponseBodyT]) adaptWithConfig(database *sql.DB, logger *slog.Logger, cfg registrationConfig) http.HandlerFunc {
+	return adaptHandler(database, logger, cfg, th.handler)
+}
+
 // PendingRoute stores route information for handlers that need to be registered later
 type PendingRoute struct {
 	Method          string
@@ -82,11 +109,37 @@ type PendingRoute struct {
 	Handler         AdaptableHandler // Interface that knows how to adapt itself
 	RouteInfo       RouteInfo        // Complete route metadata for documentation
 	MiddlewareNames []string         // Names of middleware functions applied to this route
+	HandlerName     string           // Name of the base handler function passed to MakeHandler
+
+	httpMiddleware []func(http.Handler) http.Handler // Group middleware wrapped around the adapted handler
+	hooks          []Hook                            // Group hooks wrapped around the typed handler
+	providers      []map[reflect.Type]provider       // Providers of the registries the route was mounted from, outermost first
+	servicesChecks []func(services any) error        // RequireServices checks of the registry the route was mounted from
 }
 
 // registrationConfig holds optional configuration applied during route registration.
 type registrationConfig struct {
-	services any
+	services       any
+	errorFormat    core.ErrorFormat
+	errorMode      core.ErrorMode
+	messageCatalog *core.MessageCatalog
+
+	pgErrorTranslator   *core.PgErrorTranslator
+	deprecationObserver DeprecationObserver
+	deprecationClients  map[string]bool
+	versioning          VersioningConfig
+	debugRoutesGuard    RouteGuard
+
+	dependencies *routeDependencies // Providers of the route being adapted, set per route by RegisterWithRouter
+}
+
+// newRegistrationConfig applies opts in order to an empty registrationConfig.
+func newRegistrationConfig(opts []RegistrationOption) registrationConfig {
+	cfg := registrationConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
 }
 
 // RegistrationOption configures how routes are registered with the router.
@@ -105,10 +158,67 @@ func WithServices(services any) RegistrationOption {
 	}
 }
 
+// WithErrorFormat selects the wire format for error responses written by adapted handlers.
+// The default is core.ErrorFormatEnvelope; use core.ErrorFormatProblem for RFC 9457
+// application/problem+json responses.
+//
+// Usage:
+//
+//	registry.RegisterWithRouter(r, db, logger, handler.WithErrorFormat(core.ErrorFormatProblem))
+func WithErrorFormat(format core.ErrorFormat) RegistrationOption {
+	return func(cfg *registrationConfig) {
+		cfg.errorFormat = format
+	}
+}
+
+// WithErrorMode controls whether unexpected errors expose their text to clients.
+// The default is core.ErrorModeProduction (generic message plus correlation ID, full error
+// chain logged); core.ErrorModeDevelopment includes the error text as the detail.
+//
+// Usage:
+//
+//	registry.RegisterWithRouter(r, db, logger, handler.WithErrorMode(core.ErrorModeDevelopment))
+func WithErrorMode(mode core.ErrorMode) RegistrationOption {
+	return func(cfg *registrationConfig) {
+		cfg.errorMode = mode
+	}
+}
+
+// WithMessageCatalog selects the catalog used to localise validation and coded error messages.
+// The locale is negotiated per request from Accept-Language. Defaults to core.DefaultMessages;
+// messages the catalog does not define keep their core.DefaultMessages templates.
+//
+// Usage:
+//
+//	messages := core.NewMessageCatalog("en").Register("de", germanMessages)
+//	registry.RegisterWithRouter(r, db, logger, handler.WithMessageCatalog(messages))
+func WithMessageCatalog(catalog *core.MessageCatalog) RegistrationOption {
+	return func(cfg *registrationConfig) {
+		cfg.messageCatalog = catalog
+	}
+}
+
+// WithPgErrorTranslator translates PostgreSQL errors returned by handlers into APIErrors
+// (e.g. unique violation → 409) instead of a generic 500.
+//
+// Usage:
+//
+//	translator := core.NewPgErrorTranslator().
+//	    MapConstraint("users_email_key", core.ConstraintMapping{Field: "email", Message: "email is already registered"})
+//	registry.RegisterWithRouter(r, db, logger, handler.WithPgErrorTranslator(translator))
+func WithPgErrorTranslator(translator *core.PgErrorTranslator) RegistrationOption {
+	return func(cfg *registrationConfig) {
+		cfg.pgErrorTranslator = translator
+	}
+}
+
 // Registry holds routes for a server instance
 type Registry struct {
-	routes []PendingRoute
-	mu     sync.RWMutex
+	routes         []PendingRoute
+	hooks          []Hook
+	providers      map[reflect.Type]provider
+	servicesChecks []func(services any) error
+	mu             sync.RWMutex
 }
 
 // NewRegistry creates a new route registry for a server
@@ -121,78 +231,202 @@ func NewRegistry() *Registry {
 // MakeHandler creates a handler with automatic route registration and middleware composition
 // Usage: handler.MakeHandler(registry, RouteInfo{Method: "POST", Path: "/api/v1/endpoint"}, baseHandler, middleware...)
 // Execution order: last middleware -> ... -> first middleware -> baseHandler
+// reg is a *Registry or a *Group created by Registry.Group.
 func MakeHandler[ParamTypeT any, BodyTypeT any, ResponseBodyT any](
-	reg *Registry,
+	reg Registrar,
 	routeInfo RouteInfo,
 	baseHandler Handler[ParamTypeT, BodyTypeT, ResponseBodyT],
 	middleware ...Middleware[ParamTypeT, BodyTypeT, ResponseBodyT],
 ) Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
-	handler := baseHandler
-
 	// Extract middleware names for documentation
 	middlewareNames := make([]string, len(middleware))
 	for i, mw := range middleware {
 		middlewareNames[i] = getMiddlewareName(mw)
 	}
 
-	// Apply middleware in reverse order so the last one executes first
-	for i := len(middleware) - 1; i >= 0; i-- {
-		handler = middleware[i](handler)
-	}
+	handler := applyMiddleware(baseHandler, middleware)
 
 	// Wrap the fully composed handler in TypedHandler and register with route information
-	reg.mu.Lock()
-	reg.routes = append(reg.routes, PendingRoute{
+	reg.register(PendingRoute{
 		Method:          routeInfo.Method,
 		Path:            routeInfo.Path,
-		Handler:         TypedHandler[ParamTypeT, BodyTypeT, ResponseBodyT]{handler: handler},
+		Handler:         TypedHandler[ParamTypeT, BodyTypeT, ResponseBodyT]{handler: handler, base: baseHandler, middleware: middleware},
 		RouteInfo:       routeInfo,
 		MiddlewareNames: middlewareNames,
+		HandlerName:     funcName(baseHandler),
 	})
-	reg.mu.Unlock()
 
 	return handler
 }
 
+// applyMiddleware wraps handler in middleware in reverse order, so the last one executes first
+func applyMiddleware[ParamTypeT any, BodyTypeT any, ResponseBodyT any](
+	handler Handler[ParamTypeT, BodyTypeT, ResponseBodyT],
+	middleware []Middleware[ParamTypeT, BodyTypeT, ResponseBodyT],
+) Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// register adds a route to the registry
+func (reg *Registry) register(route PendingRoute) {
+	reg.mu.Lock()
+	reg.routes = append(reg.routes, route)
+	reg.mu.Unlock()
+}
+
 // RegisterWithRouter processes all collected routes and registers them with the chi router.
-// Pass RegistrationOption values to customize behavior (e.g., WithServices for dependency injection).
-func (reg *Registry) RegisterWithRouter(r chi.Router, database *sql.DB, logger *slog.Logger, opts ...RegistrationOption) {
+// Pass RegistrationOption values to customize behavior (e.g., WithServices for dependency injection,
+// WithErrorFormat for RFC 9457 problem+json errors).
+//
+// Issues found by Validate are logged and returned as a *RouteValidationError. Routes that
+// duplicate an earlier route or use an unsupported method are skipped, so the first registration
+// of a route wins; the other routes are registered regardless. Return the error from main to
+// refuse to start with an invalid route table. If the services do not satisfy RequireServices,
+// no route is registered and the mismatch is returned.
+func (reg *Registry) RegisterWithRouter(r chi.Router, database *sql.DB, logger *slog.Logger, opts ...RegistrationOption) error {
 	reg.mu.RLock()
 	defer reg.mu.RUnlock()
 
-	cfg := &registrationConfig{}
-	for _, opt := range opts {
-		opt(cfg)
+	cfg := newRegistrationConfig(opts)
+	if err := reg.checkServices(cfg.services); err != nil {
+		return err
+	}
+
+	// Report route table mistakes and leave out the routes chi would mishandle
+	issues := validateRoutes(reg.snapshot())
+	skipped := make(map[int]bool)
+	for _, issue := range issues {
+		if issue.skipsRoute() {
+			skipped[issue.route] = true
+			logger.Error("Route skipped", "method", issue.Method, "path", issue.Path, "issue", string(issue.Kind), "message", issue.Message)
+			continue
+		}
+		logger.Warn("Invalid route", "method", issue.Method, "path", issue.Path, "issue", string(issue.Kind), "message", issue.Message)
 	}
 
-	for _, route := range reg.routes {
-		var adaptedHandler http.HandlerFunc
-		if cfg.services != nil {
-			if sa, ok := route.Handler.(interface {
-				AdaptWithServices(*sql.DB, *slog.Logger, any) http.HandlerFunc
-			}); ok {
-				adaptedHandler = sa.AdaptWithServices(database, logger, cfg.services)
+	// Versions of the same method and path share one chi route (see WithVersioning)
+	registryProviders := reg.providerSet()
+	var keys []string
+	variants := make(map[string][]routeVariant)
+	for i, route := range reg.routes {
+		if skipped[i] {
+			continue
+		}
+
+		// Registry hooks run outside group hooks
+		if hooks := append(append([]Hook(nil), reg.hooks...), route.hooks...); len(hooks) > 0 {
+			if th, ok := route.Handler.(hookable); ok {
+				route.Handler = th.withHooks(route.RouteInfo, hooks)
 			} else {
-				adaptedHandler = route.Handler.Adapt(database, logger)
+				logger.Warn("Hooks not applied to custom route handler", "method", route.Method, "path", route.Path)
 			}
-		} else {
-			adaptedHandler = route.Handler.Adapt(database, logger)
 		}
-		registerRoute(r, route.Method, route.Path, adaptedHandler)
+
+		// Registry providers are the outermost dependency scope
+		routeCfg := cfg
+		providers := route.providers
+		if registryProviders != nil {
+			providers = append([]map[reflect.Type]provider{registryProviders}, providers...)
+		}
+		if len(providers) > 0 {
+			routeCfg.dependencies = &routeDependencies{providers: providers, route: route.RouteInfo}
+		}
+
+		h := adaptRoute(route, database, logger, routeCfg)
+		if route.RouteInfo.CacheControl != "" {
+			h = withCacheControl(route.RouteInfo.CacheControl, h)
+		}
+		if route.RouteInfo.IsDeprecated() {
+			h = withDeprecation(route.RouteInfo, cfg.deprecationObserver, cfg.deprecationClients, h)
+		}
+		h = applyHTTPMiddleware(h, route.httpMiddleware)
+
+		key := route.Method + " " + route.Path
+		if _, ok := variants[key]; !ok {
+			keys = append(keys, key)
+		}
+		variants[key] = append(variants[key], routeVariant{route: route, handler: h})
+	}
+
+	for _, key := range keys {
+		routeVariants := variants[key]
+		if isVersioned(routeVariants) {
+			route := routeVariants[0].route
+			registerRoute(r, route.Method, route.Path, versionDispatcher(cfg, routeVariants))
+			continue
+		}
+		for _, variant := range routeVariants {
+			registerRoute(r, variant.route.Method, variant.route.Path, variant.handler)
+		}
+	}
+
+	if cfg.debugRoutesGuard != nil {
+		r.Get(DebugRoutesPath, reg.debugRoutesHandler(cfg))
+	}
+
+	if len(issues) > 0 {
+		return &RouteValidationError{Issues: issues}
+	}
+	return nil
+}
+
+// adaptRoute converts a pending route's handler to http.HandlerFunc, preferring the
+// config-aware adapter and falling back to the AdaptableHandler methods for custom implementations
+func adaptRoute(route PendingRoute, database *sql.DB, logger *slog.Logger, cfg registrationConfig) http.HandlerFunc {
+	if ca, ok := route.Handler.(interface {
+		adaptWithConfig(*sql.DB, *slog.Logger, registrationConfig) http.HandlerFunc
+	}); ok {
+		return ca.adaptWithConfig(database, logger, cfg)
+	}
+	if cfg.services != nil {
+		if sa, ok := route.Handler.(interface {
+			AdaptWithServices(*sql.DB, *slog.Logger, any) http.HandlerFunc
+		}); ok {
+			return sa.AdaptWithServices(database, logger, cfg.services)
+		}
 	}
+	return route.Handler.Adapt(database, logger)
 }
 
 // GetRoutes returns a copy of all collected routes for reflection/documentation
 func (reg *Registry) GetRoutes() []PendingRoute {
 	reg.mu.RLock()
 	defer reg.mu.RUnlock()
+	return reg.snapshot()
+}
 
+// snapshot copies the routes with their applied middleware names; the caller holds reg.mu
+func (reg *Registry) snapshot() []PendingRoute {
 	// Return a copy to prevent external modifications
 	routes := make([]PendingRoute, len(reg.routes))
-	copy(routes, reg.routes)
+	for i, route := range reg.routes {
+		routes[i] = route
+		routes[i].MiddlewareNames = appliedMiddlewareNames(route, reg.hooks)
+	}
 	return routes
 }
 
+// appliedMiddlewareNames lists the names of everything wrapped around a route's handler,
+// innermost first like the MakeHandler arguments: group hooks, registry hooks,
+// route middleware, then group HTTP middleware
+func appliedMiddlewareNames(route PendingRoute, registryHooks []Hook) []string {
+	var names []string
+	for i := len(route.hooks) - 1; i >= 0; i-- {
+		names = append(names, funcName(route.hooks[i]))
+	}
+	for i := len(registryHooks) - 1; i >= 0; i-- {
+		names = append(names, funcName(registryHooks[i]))
+	}
+	names = append(names, route.MiddlewareNames...)
+	for i := len(route.httpMiddleware) - 1; i >= 0; i-- {
+		names = append(names, funcName(route.httpMiddleware[i]))
+	}
+	return names
+}
+
 // registerRoute helper function to reduce code duplication
 func registerRoute(r chi.Router, method, path string, handler http.HandlerFunc) {
 	switch method {
@@ -215,8 +449,16 @@ func registerRoute(r chi.Router, method, path string, handler http.HandlerFunc)
 
 // getMiddlewareName extracts the function name from a middleware function using reflection
 func getMiddlewareName[ParamTypeT any, BodyTypeT any, ResponseBodyT any](middleware Middleware[ParamTypeT, BodyTypeT, ResponseBodyT]) string {
+	return funcName(middleware)
+}
+
+// closureNamePattern matches the runtime name suffix of closures (func1, func2, ...)
+var closureNamePattern = regexp.MustCompile(`^func\d+$`)
+
+// funcName extracts the name of any function value using reflection
+func funcName(fn any) string {
 	// Get the function value using reflection
-	middlewareValue := reflect.ValueOf(middleware)
+	middlewareValue := reflect.ValueOf(fn)
 
 	// Get the runtime function pointer and its name
 	middlewarePtr := middlewareValue.Pointer()
@@ -238,7 +480,11 @@ func getMiddlewareName[ParamTypeT any, BodyTypeT any, ResponseBodyT any](middlew
 	}
 
 	// Fallback: try standard parsing for non-generic functions
-	parts := strings.Split(fullName, ".")
+	// Closures returned by constructors (e.g. "middleware.Timeout.func1") are named after the constructor
+	parts := strings.Split(strings.TrimSuffix(fullName, "-fm"), ".")
+	for len(parts) > 1 && closureNamePattern.MatchString(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+	}
 	if len(parts) > 0 {
 		lastName := parts[len(parts)-1]
 		// Remove any generic type information (e.g., "RequireAuth[...]")
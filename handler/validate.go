@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RouteIssueKind classifies a problem found by Registry.Validate
+type RouteIssueKind string
+
+const (
+	IssueDuplicateRoute   RouteIssueKind = "duplicate_route"    // Same method, path pattern and version registered twice
+	IssueUnknownMethod    RouteIssueKind = "unknown_method"     // Method the router cannot register (routes are skipped)
+	IssueUnknownPathParam RouteIssueKind = "unknown_path_param" // param tag without a matching {placeholder} in the path
+	IssueUnboundPathParam RouteIssueKind = "unbound_path_param" // {placeholder} without a matching param tag
+	IssueBodyNotAllowed   RouteIssueKind = "body_not_allowed"   // Body parsing middleware on a GET, HEAD or DELETE route
+)
+
+// RouteIssue is one problem in a route table
+type RouteIssue struct {
+	Method  string
+	Path    string
+	Kind    RouteIssueKind
+	Message string
+
+	route int // Index of the route in the validated table
+}
+
+// skipsRoute reports whether RegisterWithRouter leaves the route out: a duplicate would
+// silently replace the route registered first, and the router cannot serve an unknown method
+func (issue RouteIssue) skipsRoute() bool {
+	return issue.Kind == IssueDuplicateRoute || issue.Kind == IssueUnknownMethod
+}
+
+// String formats the issue as "METHOD /path: message"
+func (issue RouteIssue) String() string {
+	return fmt.Sprintf("%s %s: %s", issue.Method, issue.Path, issue.Message)
+}
+
+// RouteValidationError lists every issue found in a route table
+type RouteValidationError struct {
+	Issues []RouteIssue
+}
+
+// Error implements the error interface
+func (e *RouteValidationError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.String()
+	}
+	return fmt.Sprintf("invalid route table (%d issues): %s", len(e.Issues), strings.Join(messages, "; "))
+}
+
+// supportedMethods are the methods registerRoute can register
+var supportedMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "DELETE": true, "PATCH": true, "HEAD": true, "OPTIONS": true,
+}
+
+// bodyParsers are the names of middleware that read the request body
+var bodyParsers = map[string]bool{
+	"ParseBody": true, "ParseJSON": true, "ParseCSV": true,
+}
+
+// Validate checks the route table for duplicate routes, unknown methods, param tags that do
+// not match the path placeholders, and body parsing on GET, HEAD and DELETE routes.
+// It returns a *RouteValidationError listing every issue, or nil. RegisterWithRouter returns
+// the same error and skips duplicate routes and unknown methods; call Validate to check a
+// registry without registering it.
+//
+// Example:
+//
+//	if err := reg.Validate(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (reg *Registry) Validate() error {
+	issues := validateRoutes(reg.GetRoutes())
+	if len(issues) == 0 {
+		return nil
+	}
+	return &RouteValidationError{Issues: issues}
+}
+
+// ServedRoutes returns the routes RegisterWithRouter serves, in registration order: the routes of
+// GetRoutes without those it skips because they duplicate an earlier route or use an unsupported method
+func (reg *Registry) ServedRoutes() []PendingRoute {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	routes := reg.snapshot()
+	skipped := make(map[int]bool)
+	for _, issue := range validateRoutes(routes) {
+		if issue.skipsRoute() {
+			skipped[issue.route] = true
+		}
+	}
+	served := make([]PendingRoute, 0, len(routes))
+	for i, route := range routes {
+		if !skipped[i] {
+			served = append(served, route)
+		}
+	}
+	return served
+}
+
+// validateRoutes returns the issues of a route table in registration order
+func validateRoutes(routes []PendingRoute) []RouteIssue {
+	var issues []RouteIssue
+	index := 0
+	report := func(route PendingRoute, kind RouteIssueKind, format string, args ...any) {
+		issues = append(issues, RouteIssue{
+			Method:  route.Method,
+			Path:    route.Path,
+			Kind:    kind,
+			Message: fmt.Sprintf(format, args...),
+			route:   index,
+		})
+	}
+
+	// Routes seen per method and normalized pattern, in registration order
+	type seenRoute struct {
+		version string // "" when unversioned
+		path    string
+	}
+	seen := make(map[string][]seenRoute)
+	for i, route := range routes {
+		index = i
+		if !supportedMethods[route.Method] {
+			report(route, IssueUnknownMethod, "unsupported method %q", route.Method)
+			continue
+		}
+
+		// {id} and {userID} in the same position are the same chi route
+		placeholders := pathPlaceholders(route.Path)
+		key := route.Method + " " + route.Path
+		for _, name := range placeholders {
+			key = strings.Replace(key, name, "{}", 1)
+		}
+		// Distinct versions of one route are negotiated, not duplicates
+		version := normalizeVersion(route.RouteInfo.Version)
+		var previous string
+		conflict := false
+		for _, other := range seen[key] {
+			// An unversioned route conflicts with any other; versions must share one exact pattern
+			if other.version == version || other.version == "" || version == "" || other.path != route.Path {
+				previous, conflict = other.path, true
+				break
+			}
+		}
+		if conflict {
+			report(route, IssueDuplicateRoute, "conflicts with %s %s", route.Method, previous)
+		} else {
+			seen[key] = append(seen[key], seenRoute{version: version, path: route.Path})
+		}
+
+		if typed, ok := route.Handler.(typedRoute); ok {
+			paramsType, _, _ := typed.routeTypes()
+			issues = append(issues, validatePathParams(route, i, placeholders, paramTags(paramsType))...)
+		}
+
+		if route.Method == "GET" || route.Method == "HEAD" || route.Method == "DELETE" {
+			for _, name := range route.MiddlewareNames {
+				if bodyParsers[name] {
+					report(route, IssueBodyNotAllowed, "%s reads a request body, which %s requests should not carry", name, route.Method)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// validatePathParams compares a route's path placeholders with its param tags.
+// Placeholders are only required to be bound when the params type declares param tags.
+func validatePathParams(route PendingRoute, index int, placeholders []string, tags []string) []RouteIssue {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	var issues []RouteIssue
+	inPath := make(map[string]bool, len(placeholders))
+	for _, placeholder := range placeholders {
+		inPath[placeholderName(placeholder)] = true
+	}
+	tagged := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tagged[tag] = true
+		if !inPath[tag] {
+			issues = append(issues, RouteIssue{
+				Method:  route.Method,
+				Path:    route.Path,
+				Kind:    IssueUnknownPathParam,
+				Message: fmt.Sprintf("param tag %q has no {%s} placeholder in the path", tag, tag),
+				route:   index,
+			})
+		}
+	}
+	for _, placeholder := range placeholders {
+		if name := placeholderName(placeholder); !tagged[name] {
+			issues = append(issues, RouteIssue{
+				Method:  route.Method,
+				Path:    route.Path,
+				Kind:    IssueUnboundPathParam,
+				Message: fmt.Sprintf("placeholder %s has no matching param tag", placeholder),
+				route:   index,
+			})
+		}
+	}
+	return issues
+}
+
+// pathPlaceholders returns the {name} and {name:regexp} placeholders of a chi route pattern
+func pathPlaceholders(path string) []string {
+	var placeholders []string
+	depth, start := 0, 0
+	for i, c := range path {
+		switch c {
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				placeholders = append(placeholders, path[start:i+1])
+			}
+		}
+	}
+	return placeholders
+}
+
+// placeholderName returns "id" for "{id}" and "{id:[0-9]+}"
+func placeholderName(placeholder string) string {
+	name := strings.TrimSuffix(strings.TrimPrefix(placeholder, "{"), "}")
+	if colon := strings.Index(name, ":"); colon != -1 {
+		name = name[:colon]
+	}
+	return name
+}
+
+// paramTags returns the param tags of a params struct, including fields promoted from
+// untagged embedded structs as ParseParams reads them
+func paramTags(t reflect.Type) []string {
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var tags []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct &&
+			field.Tag.Get("param") == "" && field.Tag.Get("query") == "" {
+			tags = append(tags, paramTags(field.Type)...)
+			continue
+		}
+		if tag := field.Tag.Get("param"); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// typedRoute is implemented by TypedHandler to expose its type parameters
+type typedRoute interface {
+	routeTypes() (params, body, response reflect.Type)
+}
+
+// routeTypes returns the handler's ParamTypeT, BodyTypeT and ResponseBodyT
+func (th TypedHandler[ParamTypeT, BodyTypeT, ResponseBodyT]) routeTypes() (params, body, response reflect.Type) {
+	return reflect.TypeFor[ParamTypeT](), reflect.TypeFor[BodyTypeT](), reflect.TypeFor[ResponseBodyT]()
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+)
+
+// DefaultVersionHeader is the request header that names the API version when
+// VersioningConfig.Header is empty
+const DefaultVersionHeader = "API-Version"
+
+// VersioningConfig configures how RegisterWithRouter selects between handlers registered for
+// the same method and path with different RouteInfo.Version values.
+//
+// The version comes from the Header ("API-Version: 2") or, failing that, from a vendor media
+// type in Accept ("application/vnd.myapp.v2+json"). Versions compare without a leading "v",
+// so "2" selects a route declared with Version "v2".
+type VersioningConfig struct {
+	Vendor  string // Vendor of Accept media types, e.g. "myapp"; empty accepts any vendor
+	Header  string // Request header naming the version; defaults to DefaultVersionHeader
+	Default string // Version served when the request names none; defaults to the first registered
+}
+
+// WithVersioning configures API version negotiation for routes registered in several versions.
+// Without it, versions are still negotiated using the defaults of VersioningConfig.
+//
+// Usage:
+//
+//	var _ = handler.MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users/{id}", Version: "v1"}, GetUserV1, ...)
+//	var _ = handler.MakeHandler(reg, RouteInfo{Method: "GET", Path: "/users/{id}", Version: "v2"}, GetUserV2, ...)
+//	registry.RegisterWithRouter(r, db, logger, handler.WithVersioning(handler.VersioningConfig{Vendor: "myapp", Default: "v1"}))
+func WithVersioning(config VersioningConfig) RegistrationOption {
+	return func(cfg *registrationConfig) {
+		cfg.versioning = config
+	}
+}
+
+// routeVariant is one adapted version of a method and path
+type routeVariant struct {
+	route   PendingRoute
+	handler http.HandlerFunc
+}
+
+// isVersioned reports whether variants are distinct versions of one route, which
+// RegisterWithRouter serves through versionDispatcher instead of letting the last one win
+func isVersioned(variants []routeVariant) bool {
+	if len(variants) < 2 {
+		return false
+	}
+	seen := make(map[string]bool, len(variants))
+	for _, variant := range variants {
+		version := normalizeVersion(variant.route.RouteInfo.Version)
+		if version == "" || seen[version] {
+			return false
+		}
+		seen[version] = true
+	}
+	return true
+}
+
+// versionDispatcher serves each request with the variant of the negotiated version.
+// Requests for an unknown version receive 406 Not Acceptable.
+func versionDispatcher(cfg registrationConfig, variants []routeVariant) http.HandlerFunc {
+	config := cfg.versioning
+	header := config.Header
+	if header == "" {
+		header = DefaultVersionHeader
+	}
+
+	byVersion := make(map[string]routeVariant, len(variants))
+	supported := make([]string, len(variants))
+	for i, variant := range variants {
+		byVersion[normalizeVersion(variant.route.RouteInfo.Version)] = variant
+		supported[i] = variant.route.RouteInfo.Version
+	}
+	defaultVersion := normalizeVersion(config.Default)
+	if defaultVersion == "" {
+		defaultVersion = normalizeVersion(variants[0].route.RouteInfo.Version)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.Header().Add("Vary", header)
+
+		version, r := requestedVersion(r, header, config.Vendor)
+		if version == "" {
+			version = defaultVersion
+		}
+
+		variant, ok := byVersion[version]
+		if !ok {
+			core.WriteAPIError(w, cfg.withRequestContext(r), *core.NewAPIError(http.StatusNotAcceptable,
+				"Unsupported API version", "Supported versions: "+strings.Join(supported, ", ")))
+			return
+		}
+
+		w.Header().Set(header, variant.route.RouteInfo.Version)
+		variant.handler(w, r)
+	}
+}
+
+// requestedVersion returns the normalized version named by the request, or "".
+// A vendor media type in Accept is replaced with application/json in the returned request,
+// so response content negotiation still finds the JSON encoder.
+func requestedVersion(r *http.Request, header, vendor string) (string, *http.Request) {
+	if version := normalizeVersion(r.Header.Get(header)); version != "" {
+		return version, r
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return "", r
+	}
+	ranges := strings.Split(accept, ",")
+	for i, mediaRange := range ranges {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(mediaRange), ";")
+		version, ok := vendorVersion(strings.TrimSpace(mediaType), vendor)
+		if !ok {
+			continue
+		}
+
+		ranges[i] = "application/json"
+		if params != "" {
+			ranges[i] += ";" + params
+		}
+		r = r.Clone(r.Context())
+		r.Header.Set("Accept", strings.Join(ranges, ", "))
+		return version, r
+	}
+	return "", r
+}
+
+// vendorVersion extracts the version from "application/vnd.<vendor>.v<N>+json" (or without +json)
+func vendorVersion(mediaType, vendor string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.ToLower(mediaType), "application/vnd.")
+	if !ok {
+		return "", false
+	}
+	rest, _, _ = strings.Cut(rest, "+")
+
+	dot := strings.LastIndex(rest, ".")
+	if dot == -1 {
+		return "", false
+	}
+	if vendor != "" && rest[:dot] != strings.ToLower(vendor) {
+		return "", false
+	}
+	if segment := rest[dot+1:]; len(segment) > 1 && segment[0] == 'v' {
+		return normalizeVersion(segment), true
+	}
+	return "", false
+}
+
+// normalizeVersion lowercases a version and strips a leading "v", so "V2", "v2" and "2" match
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+}
@@ -0,0 +1,41 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/platform-smith-labs/japi-core/v3/db"
+)
+
+// Database checks a database connection pool with db.HealthCheckContext (a ping)
+func Database(database *sql.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		return db.HealthCheckContext(ctx, database)
+	})
+}
+
+// HTTP checks a downstream service by sending GET url and expecting a 2xx response.
+// A nil client uses http.DefaultClient; the check's timeout bounds the request.
+func HTTP(client *http.Client, url string) Checker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,326 @@
+// Package health provides liveness, readiness and startup probes backed by a registry
+// of named checkers (database, disk, downstream services, ...).
+//
+// Each check runs with its own timeout and may cache its result, so frequent probes do not
+// hammer dependencies. Critical checks take the service out of rotation when they fail;
+// non-critical checks only mark it degraded.
+//
+// Example:
+//
+//	checks := health.NewRegistry(health.WithStartupProbe())
+//	checks.Register("database", health.Database(database), health.WithTimeout(time.Second))
+//	checks.Register("search", health.HTTP(nil, "http://search:9200/_cluster/health"),
+//	    health.NonCritical(), health.WithCacheTTL(10*time.Second))
+//	checks.RegisterRoutes(r) // GET /livez, /readyz, /startupz
+//
+//	// after migrations and cache warm-up
+//	checks.MarkStarted()
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Status is the state of a single check or of the service as a whole
+type Status string
+
+const (
+	StatusUp       Status = "up"       // All checks pass
+	StatusDegraded Status = "degraded" // Only non-critical checks fail; the service still serves traffic
+	StatusDown     Status = "down"     // A critical check fails
+	StatusStarting Status = "starting" // Startup has not completed (see WithStartupProbe)
+)
+
+// DefaultTimeout bounds a check that was registered without WithTimeout
+const DefaultTimeout = 2 * time.Second
+
+// Checker reports the health of one dependency; a nil error means healthy.
+// Implementations should honour ctx, which carries the check's timeout.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts an ordinary function to the Checker interface
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx)
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckOption configures a registered check
+type CheckOption func(*check)
+
+// WithTimeout bounds how long the check may run before it is reported as failed.
+// Defaults to DefaultTimeout.
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(c *check) { c.timeout = timeout }
+}
+
+// WithCacheTTL reuses the check's last result for ttl instead of running it on every probe.
+// Defaults to 0 (run on every probe).
+func WithCacheTTL(ttl time.Duration) CheckOption {
+	return func(c *check) { c.cacheTTL = ttl }
+}
+
+// NonCritical makes a failing check degrade the service instead of marking it down,
+// so readiness still succeeds (e.g. for an optional cache or search cluster)
+func NonCritical() CheckOption {
+	return func(c *check) { c.critical = false }
+}
+
+// Liveness also runs the check on the liveness probe. Keep liveness checks to failures
+// that a restart fixes (e.g. a deadlocked worker) - never external dependencies.
+func Liveness() CheckOption {
+	return func(c *check) { c.liveness = true }
+}
+
+// RegistryOption configures a Registry
+type RegistryOption func(*Registry)
+
+// WithStartupProbe makes the registry report StatusStarting - failing the startup and
+// readiness probes - until MarkStarted is called
+func WithStartupProbe() RegistryOption {
+	return func(reg *Registry) { reg.started.Store(false) }
+}
+
+// Registry holds the named checks of a service and serves its probes
+type Registry struct {
+	checks  map[string]*check
+	mu      sync.RWMutex
+	started atomic.Bool
+}
+
+// NewRegistry creates an empty health check registry
+func NewRegistry(opts ...RegistryOption) *Registry {
+	reg := &Registry{checks: make(map[string]*check)}
+	reg.started.Store(true)
+	for _, opt := range opts {
+		opt(reg)
+	}
+	return reg
+}
+
+// Register adds a check under name, replacing any check with the same name.
+// Checks are critical readiness checks unless configured otherwise.
+func (reg *Registry) Register(name string, checker Checker, opts ...CheckOption) {
+	c := &check{
+		name:     name,
+		checker:  checker,
+		timeout:  DefaultTimeout,
+		critical: true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.checks[name] = c
+}
+
+// MarkStarted ends the startup phase begun by WithStartupProbe
+func (reg *Registry) MarkStarted() {
+	reg.started.Store(true)
+}
+
+// Started reports whether startup has completed
+func (reg *Registry) Started() bool {
+	return reg.started.Load()
+}
+
+// Report is the JSON body served by the probes
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// CheckResult is the outcome of a single check
+type CheckResult struct {
+	Status    Status    `json:"status"`
+	Critical  bool      `json:"critical"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+	Cached    bool      `json:"cached,omitempty"`
+}
+
+// Liveness runs the checks registered with the Liveness option.
+// A process that is still starting is alive.
+func (reg *Registry) Liveness(ctx context.Context) Report {
+	return reg.run(ctx, func(c *check) bool { return c.liveness })
+}
+
+// Readiness runs every registered check. It reports StatusStarting until startup completes.
+func (reg *Registry) Readiness(ctx context.Context) Report {
+	if !reg.Started() {
+		return Report{Status: StatusStarting}
+	}
+	return reg.run(ctx, func(*check) bool { return true })
+}
+
+// LivenessHandler serves the liveness probe: 200 unless a liveness check is down
+func (reg *Registry) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, reg.Liveness(r.Context()))
+	})
+}
+
+// ReadinessHandler serves the readiness probe: 200 when up or degraded, 503 when down or starting
+func (reg *Registry) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, reg.Readiness(r.Context()))
+	})
+}
+
+// StartupHandler serves the startup probe: 503 until MarkStarted, then 200
+func (reg *Registry) StartupHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !reg.Started() {
+			writeReport(w, Report{Status: StatusStarting})
+			return
+		}
+		writeReport(w, Report{Status: StatusUp})
+	})
+}
+
+// RegisterRoutes mounts the probes at GET /livez, /readyz and /startupz
+func (reg *Registry) RegisterRoutes(r chi.Router) {
+	r.Method(http.MethodGet, "/livez", reg.LivenessHandler())
+	r.Method(http.MethodGet, "/readyz", reg.ReadinessHandler())
+	r.Method(http.MethodGet, "/startupz", reg.StartupHandler())
+}
+
+// run executes the selected checks concurrently and aggregates their results
+func (reg *Registry) run(ctx context.Context, include func(*check) bool) Report {
+	reg.mu.RLock()
+	selected := make([]*check, 0, len(reg.checks))
+	for _, c := range reg.checks {
+		if include(c) {
+			selected = append(selected, c)
+		}
+	}
+	reg.mu.RUnlock()
+	sort.Slice(selected, func(i, j int) bool { return selected[i].name < selected[j].name })
+
+	results := make([]CheckResult, len(selected))
+	var wg sync.WaitGroup
+	for i, c := range selected {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.result(ctx)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(selected))}
+	for i, c := range selected {
+		result := results[i]
+		report.Checks[c.name] = result
+		if result.Status != StatusDown {
+			continue
+		}
+		if c.critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// writeReport writes report as JSON with the probe status code for its status
+func writeReport(w http.ResponseWriter, report Report) {
+	status := http.StatusOK
+	if report.Status == StatusDown || report.Status == StatusStarting {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// check is a registered checker with its settings and cached result
+type check struct {
+	name     string
+	checker  Checker
+	timeout  time.Duration
+	cacheTTL time.Duration
+	critical bool
+	liveness bool
+
+	// mu serialises runs so concurrent probes share one result when caching is enabled
+	mu   sync.Mutex
+	last *CheckResult
+}
+
+// result returns the cached result while it is fresh, otherwise runs the check
+func (c *check) result(ctx context.Context) CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last != nil && c.cacheTTL > 0 && time.Since(c.last.CheckedAt) < c.cacheTTL {
+		cached := *c.last
+		cached.Cached = true
+		return cached
+	}
+
+	started := time.Now()
+	err := c.execute(ctx)
+	result := CheckResult{
+		Status:    StatusUp,
+		Critical:  c.critical,
+		Duration:  time.Since(started).Round(time.Microsecond).String(),
+		CheckedAt: started,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	// A probe cancelled by its caller says nothing about the dependency - don't cache it
+	if ctx.Err() == nil {
+		c.last = &result
+	}
+	return result
+}
+
+// execute runs the checker under the check's timeout. Checkers that ignore ctx are
+// abandoned when the timeout expires, and panics are reported as failures.
+func (c *check) execute(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- fmt.Errorf("check panicked: %v", recovered)
+			}
+		}()
+		done <- c.checker.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("check timed out after %s", c.timeout)
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,182 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// probe serves path on a router with the registry's routes and decodes the report
+func probe(t *testing.T, reg *Registry, path string) (int, Report) {
+	t.Helper()
+	r := chi.NewRouter()
+	reg.RegisterRoutes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid report JSON: %v", err)
+	}
+	return rec.Code, report
+}
+
+func healthy(context.Context) error { return nil }
+func failing(context.Context) error { return errors.New("connection refused") }
+
+// TestReadiness verifies status aggregation across critical and non-critical checks
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		register   func(reg *Registry)
+		wantCode   int
+		wantStatus Status
+	}{
+		{"no checks", func(reg *Registry) {}, http.StatusOK, StatusUp},
+		{"all up", func(reg *Registry) {
+			reg.Register("database", CheckerFunc(healthy))
+		}, http.StatusOK, StatusUp},
+		{"non-critical failure degrades", func(reg *Registry) {
+			reg.Register("database", CheckerFunc(healthy))
+			reg.Register("search", CheckerFunc(failing), NonCritical())
+		}, http.StatusOK, StatusDegraded},
+		{"critical failure is down", func(reg *Registry) {
+			reg.Register("database", CheckerFunc(failing))
+			reg.Register("search", CheckerFunc(failing), NonCritical())
+		}, http.StatusServiceUnavailable, StatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := NewRegistry()
+			tt.register(reg)
+
+			code, report := probe(t, reg, "/readyz")
+			if code != tt.wantCode || report.Status != tt.wantStatus {
+				t.Errorf("Expected %d %s, got %d %s", tt.wantCode, tt.wantStatus, code, report.Status)
+			}
+		})
+	}
+
+	t.Run("reports check details", func(t *testing.T) {
+		reg := NewRegistry()
+		reg.Register("database", CheckerFunc(failing))
+
+		_, report := probe(t, reg, "/readyz")
+		result := report.Checks["database"]
+		if result.Status != StatusDown || result.Error != "connection refused" || !result.Critical {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+	})
+}
+
+// TestLiveness verifies only liveness checks run on /livez
+func TestLiveness(t *testing.T) {
+	reg := NewRegistry(WithStartupProbe())
+	reg.Register("database", CheckerFunc(failing))
+	reg.Register("worker", CheckerFunc(healthy), Liveness())
+
+	code, report := probe(t, reg, "/livez")
+	if code != http.StatusOK || report.Status != StatusUp {
+		t.Errorf("Expected 200 up, got %d %s", code, report.Status)
+	}
+	if _, ok := report.Checks["database"]; ok {
+		t.Error("Readiness-only check ran on liveness probe")
+	}
+	if _, ok := report.Checks["worker"]; !ok {
+		t.Error("Expected worker check on liveness probe")
+	}
+}
+
+// TestStartupProbe verifies readiness and startup fail until MarkStarted
+func TestStartupProbe(t *testing.T) {
+	reg := NewRegistry(WithStartupProbe())
+	reg.Register("database", CheckerFunc(healthy))
+
+	for _, path := range []string{"/startupz", "/readyz"} {
+		if code, report := probe(t, reg, path); code != http.StatusServiceUnavailable || report.Status != StatusStarting {
+			t.Errorf("%s before start: got %d %s", path, code, report.Status)
+		}
+	}
+
+	reg.MarkStarted()
+	for _, path := range []string{"/startupz", "/readyz"} {
+		if code, report := probe(t, reg, path); code != http.StatusOK || report.Status != StatusUp {
+			t.Errorf("%s after start: got %d %s", path, code, report.Status)
+		}
+	}
+}
+
+// TestCheckTimeoutAndCache verifies per-check timeouts, caching and panic handling
+func TestCheckTimeoutAndCache(t *testing.T) {
+	t.Run("timeout fails a hung check", func(t *testing.T) {
+		reg := NewRegistry()
+		reg.Register("hung", CheckerFunc(func(ctx context.Context) error {
+			time.Sleep(time.Second) // ignores ctx
+			return nil
+		}), WithTimeout(20*time.Millisecond))
+
+		started := time.Now()
+		report := reg.Readiness(context.Background())
+		if time.Since(started) > 500*time.Millisecond {
+			t.Error("Probe waited for a hung check")
+		}
+		if report.Status != StatusDown || report.Checks["hung"].Error == "" {
+			t.Errorf("Expected timed out check, got %+v", report)
+		}
+	})
+
+	t.Run("cached results are reused", func(t *testing.T) {
+		var calls atomic.Int32
+		reg := NewRegistry()
+		reg.Register("database", CheckerFunc(func(context.Context) error {
+			calls.Add(1)
+			return nil
+		}), WithCacheTTL(time.Minute))
+
+		reg.Readiness(context.Background())
+		report := reg.Readiness(context.Background())
+		if calls.Load() != 1 {
+			t.Errorf("Expected 1 call, got %d", calls.Load())
+		}
+		if !report.Checks["database"].Cached {
+			t.Error("Expected cached result")
+		}
+	})
+
+	t.Run("panics are failures", func(t *testing.T) {
+		reg := NewRegistry()
+		reg.Register("broken", CheckerFunc(func(context.Context) error { panic("boom") }))
+
+		if report := reg.Readiness(context.Background()); report.Checks["broken"].Status != StatusDown {
+			t.Errorf("Expected panicking check to be down, got %+v", report.Checks["broken"])
+		}
+	})
+}
+
+// TestHTTPChecker verifies downstream status handling
+func TestHTTPChecker(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	checker := HTTP(server.Client(), server.URL)
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("Expected healthy downstream, got %v", err)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := checker.Check(context.Background()); err == nil {
+		t.Error("Expected error for 503 downstream")
+	}
+}
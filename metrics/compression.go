@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CompressionMetrics exports response compression statistics.
+// It implements the CompressionObserver interface of the middleware/http package.
+type CompressionMetrics struct {
+	uncompressedBytes *prometheus.CounterVec
+	compressedBytes   *prometheus.CounterVec
+	ratio             *prometheus.HistogramVec
+}
+
+// NewCompressionMetrics registers compression metrics with the default Prometheus registerer.
+//
+// Metrics tracked:
+//   - http_compression_uncompressed_bytes_total{encoding} - Response bytes before compression
+//   - http_compression_compressed_bytes_total{encoding} - Response bytes sent after compression
+//   - http_compression_ratio{encoding} - Compressed/uncompressed size per response (lower is better)
+//
+// Example:
+//
+//	compression := metrics.NewCompressionMetrics(metrics.DefaultMetricsOptions())
+//	cfg := httpMiddleware.DefaultCompressionConfig()
+//	cfg.Observer = compression
+//	r := router.NewChiRouterWithOptions(router.WithCompression(cfg))
+func NewCompressionMetrics(opts MetricsOptions) *CompressionMetrics {
+	return newCompressionMetricsWithRegisterer(opts, prometheus.DefaultRegisterer)
+}
+
+// newCompressionMetricsWithRegisterer allows injection of a custom registerer for testing
+func newCompressionMetricsWithRegisterer(opts MetricsOptions, registerer prometheus.Registerer) *CompressionMetrics {
+	m := &CompressionMetrics{
+		uncompressedBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: opts.Namespace,
+				Subsystem: opts.Subsystem,
+				Name:      "compression_uncompressed_bytes_total",
+				Help:      "Total response bytes before compression",
+			},
+			[]string{"encoding"},
+		),
+		compressedBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: opts.Namespace,
+				Subsystem: opts.Subsystem,
+				Name:      "compression_compressed_bytes_total",
+				Help:      "Total response bytes sent after compression",
+			},
+			[]string{"encoding"},
+		),
+		ratio: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: opts.Namespace,
+				Subsystem: opts.Subsystem,
+				Name:      "compression_ratio",
+				Help:      "Compressed to uncompressed size ratio per response",
+				Buckets:   []float64{0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.8, 1},
+			},
+			[]string{"encoding"},
+		),
+	}
+
+	registerer.MustRegister(m.uncompressedBytes)
+	registerer.MustRegister(m.compressedBytes)
+	registerer.MustRegister(m.ratio)
+
+	return m
+}
+
+// ObserveCompression records the sizes of one compressed response
+func (m *CompressionMetrics) ObserveCompression(encoding string, uncompressedBytes, compressedBytes int64) {
+	m.uncompressedBytes.WithLabelValues(encoding).Add(float64(uncompressedBytes))
+	m.compressedBytes.WithLabelValues(encoding).Add(float64(compressedBytes))
+	if uncompressedBytes > 0 {
+		m.ratio.WithLabelValues(encoding).Observe(float64(compressedBytes) / float64(uncompressedBytes))
+	}
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestCompressionMetrics verifies byte counters and the ratio histogram
+func TestCompressionMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := newCompressionMetricsWithRegisterer(DefaultMetricsOptions(), reg)
+
+	m.ObserveCompression("gzip", 4000, 1000)
+	m.ObserveCompression("gzip", 2000, 500)
+
+	if got := testutil.ToFloat64(m.uncompressedBytes.WithLabelValues("gzip")); got != 6000 {
+		t.Errorf("Expected 6000 uncompressed bytes, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.compressedBytes.WithLabelValues("gzip")); got != 1500 {
+		t.Errorf("Expected 1500 compressed bytes, got %v", got)
+	}
+	if got := testutil.CollectAndCount(m.ratio); got != 1 {
+		t.Errorf("Expected one ratio series, got %d", got)
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DeprecationMetrics counts calls to deprecated routes per client.
+// It implements the DeprecationObserver interface of the handler package.
+type DeprecationMetrics struct {
+	calls *prometheus.CounterVec
+}
+
+// NewDeprecationMetrics registers deprecation metrics with the default Prometheus registerer.
+//
+// Metrics tracked:
+//   - http_deprecated_requests_total{method, path, client} - Calls to deprecated routes by known client
+//     (the clients passed to handler.WithDeprecationObserver, "other" for the rest)
+//
+// Example:
+//
+//	deprecations := metrics.NewDeprecationMetrics(metrics.DefaultMetricsOptions())
+//	registry.RegisterWithRouter(r, db, logger, handler.WithDeprecationObserver(deprecations, "billing-service"))
+func NewDeprecationMetrics(opts MetricsOptions) *DeprecationMetrics {
+	return newDeprecationMetricsWithRegisterer(opts, prometheus.DefaultRegisterer)
+}
+
+// newDeprecationMetricsWithRegisterer allows injection of a custom registerer for testing
+func newDeprecationMetricsWithRegisterer(opts MetricsOptions, registerer prometheus.Registerer) *DeprecationMetrics {
+	m := &DeprecationMetrics{
+		calls: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: opts.Namespace,
+				Subsystem: opts.Subsystem,
+				Name:      "deprecated_requests_total",
+				Help:      "Total requests served by deprecated routes",
+			},
+			[]string{"method", "path", "client"},
+		),
+	}
+
+	registerer.MustRegister(m.calls)
+
+	return m
+}
+
+// ObserveDeprecatedCall records one call to a deprecated route
+func (m *DeprecationMetrics) ObserveDeprecatedCall(method, path, client string) {
+	m.calls.WithLabelValues(method, path, client).Inc()
+}
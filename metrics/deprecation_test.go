@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestDeprecationMetrics verifies calls are counted per route and client
+func TestDeprecationMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := newDeprecationMetricsWithRegisterer(DefaultMetricsOptions(), reg)
+
+	m.ObserveDeprecatedCall("GET", "/api/v1/users", "billing-service")
+	m.ObserveDeprecatedCall("GET", "/api/v1/users", "billing-service")
+	m.ObserveDeprecatedCall("GET", "/api/v1/users", "curl")
+
+	if got := testutil.ToFloat64(m.calls.WithLabelValues("GET", "/api/v1/users", "billing-service")); got != 2 {
+		t.Errorf("Expected 2 calls from billing-service, got %v", got)
+	}
+	if got := testutil.CollectAndCount(m.calls); got != 2 {
+		t.Errorf("Expected two series, got %d", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/platform-smith-labs/japi-core/v3/core"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -15,6 +16,7 @@ type Collector struct {
 	requestsTotal    *prometheus.CounterVec
 	requestDuration  *prometheus.HistogramVec
 	requestsInFlight prometheus.Gauge
+	panicsTotal      *prometheus.CounterVec
 	registry         prometheus.Registerer
 }
 
@@ -53,6 +55,7 @@ func DefaultMetricsOptions() MetricsOptions {
 //   - http_requests_total{method,path,status} - Total number of HTTP requests
 //   - http_request_duration_seconds{method,path} - HTTP request latency distribution
 //   - http_requests_in_flight - Current number of HTTP requests being served
+//   - http_panics_total{method,path} - Panics recovered by core.Recoverer or typed handlers
 //
 // Example:
 //
@@ -121,6 +124,15 @@ func enablePrometheusMetricsWithRegisterer(
 				Help:      "Current number of HTTP requests being served",
 			},
 		),
+		panicsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: opts.Namespace,
+				Subsystem: opts.Subsystem,
+				Name:      "panics_total",
+				Help:      "Total number of panics recovered while serving HTTP requests",
+			},
+			[]string{"method", "path"},
+		),
 		registry: registerer,
 	}
 
@@ -128,6 +140,7 @@ func enablePrometheusMetricsWithRegisterer(
 	registerer.MustRegister(collector.requestsTotal)
 	registerer.MustRegister(collector.requestDuration)
 	registerer.MustRegister(collector.requestsInFlight)
+	registerer.MustRegister(collector.panicsTotal)
 
 	// Apply metrics middleware to router
 	router.Use(collector.middleware)
@@ -152,6 +165,9 @@ func (c *Collector) middleware(next http.Handler) http.Handler {
 		c.requestsInFlight.Inc()
 		defer c.requestsInFlight.Dec()
 
+		// Count panics recovered anywhere in the chain (core.Recoverer, typed handlers)
+		r = core.WithPanicObserver(r, c)
+
 		// Record start time
 		start := time.Now()
 
@@ -181,6 +197,11 @@ func (c *Collector) middleware(next http.Handler) http.Handler {
 	})
 }
 
+// ObservePanic counts a recovered panic; it implements core.PanicObserver
+func (c *Collector) ObservePanic(r *http.Request) {
+	c.panicsTotal.WithLabelValues(r.Method, getRoutePattern(r)).Inc()
+}
+
 // getRoutePattern extracts the route pattern from chi's route context
 // This normalizes paths like "/users/123" to "/users/{id}" to prevent metric cardinality explosion
 func getRoutePattern(r *http.Request) string {
@@ -215,3 +236,9 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	}
 	return rw.ResponseWriter.Write(b)
 }
+
+// Unwrap exposes the underlying ResponseWriter so http.ResponseController can
+// reach optional interfaces such as http.Flusher (needed for streaming and SSE)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
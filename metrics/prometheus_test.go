@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/platform-smith-labs/japi-core/v3/core"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -492,3 +493,28 @@ func BenchmarkMetricsEndpoint(b *testing.B) {
 		io.Copy(io.Discard, rec.Body)
 	}
 }
+
+// TestEnablePrometheusMetrics_PanicCounting verifies recovered panics are counted per route
+func TestEnablePrometheusMetrics_PanicCounting(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	r := chi.NewRouter()
+	r.Use(core.Recoverer)
+	enablePrometheusMetricsWithRegisterer(r, "/metrics", DefaultMetricsOptions(), reg)
+
+	r.Get("/boom/{id}", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/boom/1", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", rec.Code)
+	}
+
+	metricsRec := httptest.NewRecorder()
+	r.ServeHTTP(metricsRec, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(metricsRec.Body.String(), `http_panics_total{method="GET",path="/boom/{id}"} 1`) {
+		t.Errorf("Expected panic counter in metrics output:\n%s", metricsRec.Body.String())
+	}
+}
@@ -0,0 +1,447 @@
+package http
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported content codings
+const (
+	EncodingBrotli = "br"
+	EncodingZstd   = "zstd"
+	EncodingGzip   = "gzip"
+)
+
+// CompressionObserver receives the byte counts of every compressed response.
+// metrics.CompressionMetrics implements it to export the compression ratio.
+type CompressionObserver interface {
+	ObserveCompression(encoding string, uncompressedBytes, compressedBytes int64)
+}
+
+// CompressionConfig configures response compression
+type CompressionConfig struct {
+	// Encodings lists the codings offered, in server preference order for equal client weights
+	// Default: br, zstd, gzip
+	Encodings []string
+
+	// ContentTypes lists compressible media types; "type/*" matches a whole type
+	// Default: text/*, JSON, NDJSON, problem+json, XML, JavaScript, SVG, event streams
+	ContentTypes []string
+
+	// MinSize is the minimum body size (in bytes) worth compressing, per media type or "type/*"
+	// Default: 0 for text/event-stream (events are small and flushed individually)
+	MinSize map[string]int
+
+	// DefaultMinSize applies to media types without a MinSize entry
+	// Default: 1024
+	DefaultMinSize int
+
+	// Observer, if set, is told the uncompressed and compressed size of each compressed response
+	Observer CompressionObserver
+}
+
+// DefaultCompressionConfig returns sensible defaults for JSON APIs
+func DefaultCompressionConfig() CompressionConfig {
+	return CompressionConfig{
+		Encodings: []string{EncodingBrotli, EncodingZstd, EncodingGzip},
+		ContentTypes: []string{
+			"text/*",
+			"application/json",
+			"application/problem+json",
+			"application/x-ndjson",
+			"application/xml",
+			"application/javascript",
+			"image/svg+xml",
+		},
+		MinSize: map[string]int{
+			"text/event-stream": 0,
+		},
+		DefaultMinSize: 1024,
+	}
+}
+
+// WithCompression compresses responses using the best coding the client accepts.
+//
+// The coding is negotiated from Accept-Encoding (brotli, zstd or gzip, honouring q-values).
+// Only compressible media types are compressed, and only once the body reaches the minimum
+// size for its type; smaller bodies are sent unchanged. Responses that already carry a
+// Content-Encoding, 204/304 responses, and responses marked Cache-Control: no-transform are
+// never compressed. Strong ETags are weakened on compressed responses, since the bytes differ.
+//
+// Flushing keeps working for streamed responses (NDJSON, SSE): a flush compresses the data
+// written so far and flushes it to the client, regardless of the minimum size.
+//
+// Dependencies: None
+// Context modifications: None
+// Use: Apply to chi router via r.Use(WithCompression(DefaultCompressionConfig())),
+// or via router.WithCompression
+//
+// Example:
+//
+//	cfg := httpMiddleware.DefaultCompressionConfig()
+//	cfg.MinSize["application/json"] = 4096
+//	r.Use(httpMiddleware.WithCompression(cfg))
+func WithCompression(config CompressionConfig) func(http.Handler) http.Handler {
+	defaults := DefaultCompressionConfig()
+	if len(config.Encodings) == 0 {
+		config.Encodings = defaults.Encodings
+	}
+	// Never offer a coding without an encoder
+	config.Encodings = slices.DeleteFunc(slices.Clone(config.Encodings), func(encoding string) bool {
+		_, ok := encoderPools[encoding]
+		return !ok
+	})
+	if len(config.ContentTypes) == 0 {
+		config.ContentTypes = defaults.ContentTypes
+	}
+	if config.MinSize == nil {
+		config.MinSize = defaults.MinSize
+	}
+	if config.DefaultMinSize <= 0 {
+		config.DefaultMinSize = defaults.DefaultMinSize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Responses differ by Accept-Encoding whether or not this one ends up compressed
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), config.Encodings)
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				config:         &config,
+				encoding:       encoding,
+				status:         http.StatusOK,
+			}
+			defer cw.close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// compressWriter states
+const (
+	compressBuffering   = iota // Collecting the body until the compression decision can be made
+	compressPassthrough        // Writing the body unchanged
+	compressActive             // Writing the body through the encoder
+)
+
+// compressWriter buffers the start of a response until it knows whether compression is worthwhile
+type compressWriter struct {
+	http.ResponseWriter
+	config   *CompressionConfig
+	encoding string
+
+	state       int
+	status      int
+	wroteHeader bool
+	buf         []byte
+
+	encoder      resettableEncoder
+	counter      *countingWriter
+	uncompressed int64
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader || cw.state != compressBuffering {
+		return
+	}
+
+	// Informational responses pass straight through
+	if status >= 100 && status < 200 {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	cw.status = status
+	cw.wroteHeader = true
+
+	// Decide now when the headers already rule compression out
+	if status == http.StatusNoContent || status == http.StatusNotModified ||
+		(cw.Header().Get("Content-Type") != "" && !cw.eligible()) {
+		cw.start(false)
+		return
+	}
+
+	// A declared length settles the size question up front
+	if length, err := strconv.Atoi(cw.Header().Get("Content-Length")); err == nil {
+		cw.start(length >= cw.minSize())
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader && cw.state == compressBuffering {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	switch cw.state {
+	case compressPassthrough:
+		return cw.ResponseWriter.Write(b)
+	case compressActive:
+		cw.uncompressed += int64(len(b))
+		return cw.encoder.Write(b)
+	}
+
+	// Let net/http's sniffing happen here so the content type can be checked
+	if cw.Header().Get("Content-Type") == "" {
+		cw.Header().Set("Content-Type", http.DetectContentType(b))
+	}
+	if !cw.eligible() {
+		if err := cw.start(false); err != nil {
+			return 0, err
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= cw.minSize() {
+		if err := cw.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush sends everything written so far. A still-undecided compressible response is compressed,
+// since a flushing handler is streaming and its final size is unknown.
+func (cw *compressWriter) Flush() {
+	if cw.state == compressBuffering {
+		if !cw.wroteHeader {
+			cw.WriteHeader(http.StatusOK)
+		}
+		if cw.state == compressBuffering {
+			cw.start(cw.Header().Get("Content-Type") != "" && cw.eligible())
+		}
+	}
+
+	if cw.state == compressActive {
+		cw.encoder.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the underlying ResponseWriter so http.ResponseController can reach
+// optional interfaces such as write deadlines
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// start commits the headers and writes any buffered body, compressed or not
+func (cw *compressWriter) start(compress bool) error {
+	if compress {
+		header := cw.Header()
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+
+		cw.counter = &countingWriter{w: cw.ResponseWriter}
+		cw.encoder = getEncoder(cw.encoding, cw.counter)
+		cw.state = compressActive
+	} else {
+		cw.state = compressPassthrough
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buffered := cw.buf
+	cw.buf = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	if compress {
+		cw.uncompressed += int64(len(buffered))
+		_, err := cw.encoder.Write(buffered)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buffered)
+	return err
+}
+
+// close finishes the response after the handler returns
+func (cw *compressWriter) close() {
+	if cw.state == compressBuffering {
+		if !cw.wroteHeader && len(cw.buf) == 0 {
+			// Nothing was written; let net/http send its implicit 200
+			return
+		}
+		cw.start(false)
+		return
+	}
+
+	if cw.state == compressActive {
+		cw.encoder.Close()
+		putEncoder(cw.encoding, cw.encoder)
+		if cw.config.Observer != nil {
+			cw.config.Observer.ObserveCompression(cw.encoding, cw.uncompressed, cw.counter.n)
+		}
+	}
+}
+
+// eligible reports whether the response headers allow compression
+func (cw *compressWriter) eligible() bool {
+	header := cw.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-transform") {
+			return false
+		}
+	}
+
+	mediaType := cw.mediaType()
+	for _, compressible := range cw.config.ContentTypes {
+		if mediaTypeMatches(compressible, mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// minSize returns the compression threshold for the response's media type
+func (cw *compressWriter) minSize() int {
+	mediaType := cw.mediaType()
+	if size, ok := cw.config.MinSize[mediaType]; ok {
+		return size
+	}
+	if major, _, ok := strings.Cut(mediaType, "/"); ok {
+		if size, ok := cw.config.MinSize[major+"/*"]; ok {
+			return size
+		}
+	}
+	return cw.config.DefaultMinSize
+}
+
+// mediaType returns the response media type without parameters
+func (cw *compressWriter) mediaType() string {
+	mediaType, _, err := mime.ParseMediaType(cw.Header().Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// mediaTypeMatches reports whether pattern ("type/subtype" or "type/*") matches mediaType
+func mediaTypeMatches(pattern, mediaType string) bool {
+	if pattern == mediaType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasSuffix(prefix, "/") {
+		return strings.HasPrefix(mediaType, prefix)
+	}
+	return false
+}
+
+// negotiateEncoding picks the supported coding with the highest client weight (RFC 9110 §12.5.3).
+// Equal weights fall back to the server preference order; "" means send the response unencoded.
+func negotiateEncoding(acceptEncoding string, supported []string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	weights := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+
+		if coding == "*" {
+			wildcard = quality
+		} else if coding != "" {
+			weights[coding] = quality
+		}
+	}
+
+	type candidate struct {
+		encoding string
+		quality  float64
+	}
+	var candidates []candidate
+	for _, encoding := range supported {
+		quality, ok := weights[encoding]
+		if !ok {
+			quality = wildcard
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{encoding: encoding, quality: quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	// Stable sort keeps the server preference order among equal weights
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	return candidates[0].encoding
+}
+
+// countingWriter counts the compressed bytes written to the client
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// resettableEncoder is the common surface of the pooled gzip, brotli and zstd writers
+type resettableEncoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// encoderPools reuse encoders across responses; compressor state is expensive to allocate
+var encoderPools = map[string]*sync.Pool{
+	EncodingGzip: {New: func() any {
+		return gzip.NewWriter(io.Discard)
+	}},
+	EncodingBrotli: {New: func() any {
+		// Level 4 keeps brotli close to gzip speed while compressing better
+		return brotli.NewWriterLevel(io.Discard, 4)
+	}},
+	EncodingZstd: {New: func() any {
+		encoder, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1))
+		return encoder
+	}},
+}
+
+// getEncoder takes a pooled encoder for encoding and points it at w
+func getEncoder(encoding string, w io.Writer) resettableEncoder {
+	encoder := encoderPools[encoding].Get().(resettableEncoder)
+	encoder.Reset(w)
+	return encoder
+}
+
+// putEncoder returns an encoder to its pool, detached from the response
+func putEncoder(encoding string, encoder resettableEncoder) {
+	encoder.Reset(io.Discard)
+	encoderPools[encoding].Put(encoder)
+}
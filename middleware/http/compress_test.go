@@ -0,0 +1,158 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+)
+
+type recordingObserver struct {
+	encoding                 string
+	uncompressed, compressed int64
+}
+
+func (o *recordingObserver) ObserveCompression(encoding string, uncompressed, compressed int64) {
+	o.encoding, o.uncompressed, o.compressed = encoding, uncompressed, compressed
+}
+
+// TestWithCompression verifies negotiation, thresholds and opt-outs
+func TestWithCompression(t *testing.T) {
+	largeJSON := `{"items":"` + strings.Repeat("japi ", 1000) + `"}`
+
+	serve := func(cfg CompressionConfig, acceptEncoding string, h http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/items", nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		WithCompression(cfg)(h).ServeHTTP(rec, req)
+		return rec
+	}
+	writeJSON := func(body string, headers ...string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			for i := 0; i+1 < len(headers); i += 2 {
+				w.Header().Set(headers[i], headers[i+1])
+			}
+			io.WriteString(w, body)
+		}
+	}
+
+	t.Run("compresses with the preferred accepted coding", func(t *testing.T) {
+		observer := &recordingObserver{}
+		cfg := DefaultCompressionConfig()
+		cfg.Observer = observer
+
+		rec := serve(cfg, "gzip;q=0.5, br", writeJSON(largeJSON))
+		if got := rec.Header().Get("Content-Encoding"); got != EncodingBrotli {
+			t.Fatalf("Expected br, got %q", got)
+		}
+		decoded, err := io.ReadAll(brotli.NewReader(rec.Body))
+		if err != nil || string(decoded) != largeJSON {
+			t.Errorf("Body did not round-trip: %v", err)
+		}
+		if observer.encoding != EncodingBrotli || observer.uncompressed != int64(len(largeJSON)) || observer.compressed >= observer.uncompressed {
+			t.Errorf("Unexpected observation: %+v", observer)
+		}
+	})
+
+	t.Run("falls back to gzip", func(t *testing.T) {
+		rec := serve(DefaultCompressionConfig(), "gzip", writeJSON(largeJSON, "ETag", `"abc"`))
+		if got := rec.Header().Get("Content-Encoding"); got != EncodingGzip {
+			t.Fatalf("Expected gzip, got %q", got)
+		}
+		if got := rec.Header().Get("ETag"); got != `W/"abc"` {
+			t.Errorf("Expected weakened ETag, got %q", got)
+		}
+		reader, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatalf("Invalid gzip body: %v", err)
+		}
+		if decoded, _ := io.ReadAll(reader); string(decoded) != largeJSON {
+			t.Error("Body did not round-trip")
+		}
+	})
+
+	t.Run("skips bodies below the minimum size", func(t *testing.T) {
+		rec := serve(DefaultCompressionConfig(), "gzip", writeJSON(`{"ok":true}`))
+		if got := rec.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected no compression, got %q", got)
+		}
+		if rec.Body.String() != `{"ok":true}` {
+			t.Errorf("Unexpected body %q", rec.Body.String())
+		}
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Error("Expected Vary: Accept-Encoding")
+		}
+	})
+
+	t.Run("honours Cache-Control no-transform", func(t *testing.T) {
+		rec := serve(DefaultCompressionConfig(), "gzip", writeJSON(largeJSON, "Cache-Control", "public, no-transform"))
+		if got := rec.Header().Get("Content-Encoding"); got != "" || rec.Body.String() != largeJSON {
+			t.Errorf("Expected unmodified body, got encoding %q", got)
+		}
+	})
+
+	t.Run("skips incompressible types and identity clients", func(t *testing.T) {
+		png := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(bytes.Repeat([]byte{0}, 4096))
+		}
+		if got := serve(DefaultCompressionConfig(), "gzip", png).Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected no compression for image/png, got %q", got)
+		}
+		if got := serve(DefaultCompressionConfig(), "identity", writeJSON(largeJSON)).Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected no compression for identity, got %q", got)
+		}
+	})
+
+	t.Run("flushes streamed events", func(t *testing.T) {
+		var flushedBytes int
+		rec := serve(DefaultCompressionConfig(), "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			io.WriteString(w, "data: {\"n\":1}\n\n")
+			if err := http.NewResponseController(w).Flush(); err != nil {
+				t.Errorf("Flush failed: %v", err)
+			}
+			flushedBytes = w.(interface{ Unwrap() http.ResponseWriter }).Unwrap().(*httptest.ResponseRecorder).Body.Len()
+		})
+
+		if rec.Header().Get("Content-Encoding") != EncodingGzip || !rec.Flushed {
+			t.Fatalf("Expected flushed gzip stream, got encoding %q", rec.Header().Get("Content-Encoding"))
+		}
+		if flushedBytes == 0 {
+			t.Error("Expected the event to reach the client at flush time")
+		}
+		reader, _ := gzip.NewReader(rec.Body)
+		if decoded, _ := io.ReadAll(reader); string(decoded) != "data: {\"n\":1}\n\n" {
+			t.Errorf("Unexpected stream body %q", decoded)
+		}
+	})
+}
+
+// TestNegotiateEncoding verifies Accept-Encoding weights and wildcards
+func TestNegotiateEncoding(t *testing.T) {
+	supported := []string{EncodingBrotli, EncodingZstd, EncodingGzip}
+	tests := map[string]string{
+		"":                       "",
+		"gzip":                   EncodingGzip,
+		"gzip, br":               EncodingBrotli,
+		"br;q=0.2, zstd;q=0.9":   EncodingZstd,
+		"*":                      EncodingBrotli,
+		"*, br;q=0":              EncodingZstd,
+		"deflate, identity":      "",
+		"GZIP;q=1.0, br;q=0.999": EncodingGzip,
+	}
+	for header, want := range tests {
+		if got := negotiateEncoding(header, supported); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
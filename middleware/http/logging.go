@@ -58,3 +58,8 @@ func (w *responseWriter) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
 	w.ResponseWriter.WriteHeader(statusCode)
 }
+
+// Unwrap exposes the underlying ResponseWriter so http.ResponseController can flush streamed responses
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
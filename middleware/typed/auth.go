@@ -22,7 +22,7 @@ import (
 //     The function should accept (querier, userUUID, companyUUID) and return error
 //
 // Dependencies: jwt package, database access via ctx.DB
-// Context modifications: Sets ctx.UserUUID and ctx.CompanyUUID
+// Context modifications: Sets ctx.UserUUID and ctx.CompanyUUID (and the user logged for panics)
 // Use: Apply via MakeHandler(..., RequireAuth(secret, validator, ...), ...)
 //
 // Returns:
@@ -87,6 +87,7 @@ func RequireAuth[ParamTypeT any, BodyTypeT any, ResponseBodyT any](
 		// Set authenticated user data in context
 		ctx.UserUUID = handler.NewNullable(claims.UserUUID)
 		ctx.CompanyUUID = handler.NewNullable(claims.CompanyUUID)
+		core.SetRequestUser(r.Context(), claims.UserUUID.String())
 
 		// Call next handler with authenticated context
 		return next(ctx, w, r)
@@ -0,0 +1,127 @@
+package typed
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+// ResponseETag writes JSON responses with an ETag and answers conditional GETs with 304.
+//
+// It behaves like ResponseJSON, but encodes the response first so an ETag can be computed
+// from the body. If the response implements core.Versioned, its ResourceVersion is used
+// instead (and Last-Modified is sent when set). GET requests whose If-None-Match or
+// If-Modified-Since match receive 304 Not Modified without a body.
+//
+// Dependencies: core.ComputeETag, core.NotModified
+// Context modifications: None
+// Use: Apply via MakeHandler(getHandler, ParseParams, ResponseETag)
+//
+// Example:
+//
+//	handler := MakeHandler(reg, RouteInfo{Method: "GET", Path: "/api/v1/users/{id}", CacheControl: "private, no-cache"},
+//	    GetUser, ParseParams, ResponseETag)
+func ResponseETag[ParamTypeT any, BodyTypeT any, ResponseBodyT any](next handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT]) handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+	return ResponseETagWith[ParamTypeT, BodyTypeT, ResponseBodyT](core.ETagOptions{})(next)
+}
+
+// ResponseETagWith is like ResponseETag but with custom ETag options (e.g. weak ETags).
+//
+// Example:
+//
+//	handler := MakeHandler(reg, routeInfo, GetUser, ParseParams,
+//	    ResponseETagWith[UserParams, struct{}, User](core.ETagOptions{Weak: true}))
+func ResponseETagWith[ParamTypeT any, BodyTypeT any, ResponseBodyT any](opts core.ETagOptions) func(next handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT]) handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+	return func(next handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT]) handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+		return func(ctx handler.HandlerContext[ParamTypeT, BodyTypeT], w http.ResponseWriter, r *http.Request) (ResponseBodyT, error) {
+			// Execute the handler
+			responseData, err := next(ctx, w, r)
+			if err != nil {
+				// Don't handle errors here - let the adapter handle them
+				return responseData, err
+			}
+
+			// Apply handler.Response metadata, defaulting the status from the HTTP method
+			statusCode, responseBody := prepareResponse(w, r, responseData)
+			if !bodyAllowed(statusCode) {
+				w.WriteHeader(statusCode)
+				return responseData, nil
+			}
+
+			// Encode up front so the ETag can be derived from the exact bytes sent
+			var body bytes.Buffer
+			if err := json.NewEncoder(&body).Encode(responseBody); err != nil {
+				ctx.Logger.Error("Failed to encode JSON response", "error", err.Error(), "path", r.URL.Path)
+				return responseData, core.NewAPIError(http.StatusInternalServerError, "Failed to write response")
+			}
+
+			// Prefer the handler-supplied version over a body hash
+			var version core.ResourceVersion
+			if versioned, ok := responseBody.(core.Versioned); ok {
+				version = versioned.ResourceVersion()
+			}
+			if version.ETag == "" {
+				version.ETag = core.ComputeETag(body.Bytes(), opts.Weak)
+			}
+
+			// The client's copy is current - skip the body
+			if core.NotModified(r, version) {
+				core.WriteNotModified(w, version)
+				return responseData, nil
+			}
+
+			core.SetVersionHeaders(w, version)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(statusCode)
+			if _, err := w.Write(body.Bytes()); err != nil {
+				ctx.Logger.Error("Failed to write JSON response", "error", err.Error(), "path", r.URL.Path)
+			}
+
+			return responseData, nil
+		}
+	}
+}
+
+// Preconditions rejects writes made against a stale copy of a resource with 412.
+//
+// When the request carries If-Match or If-Unmodified-Since, current is called to load the
+// resource's present version and the conditions are checked with core.CheckPreconditions
+// before the handler runs. Requests without preconditions skip the lookup.
+// Place it after ParseParams so current can read the parsed parameters.
+//
+// Dependencies: core.CheckPreconditions
+// Context modifications: None
+// Use: Apply via MakeHandler(updateHandler, ParseParams, ParseBody, Preconditions(loadVersion), ResponseETag)
+//
+// Example:
+//
+//	loadVersion := func(ctx handler.HandlerContext[UserParams, UpdateUserBody], r *http.Request) (core.ResourceVersion, error) {
+//	    params, _ := ctx.Params.Value()
+//	    var version int
+//	    err := ctx.DB.QueryRowContext(ctx.Context, "SELECT version FROM users WHERE id = $1", params.ID).Scan(&version)
+//	    return core.ResourceVersion{ETag: core.StrongETag(strconv.Itoa(version))}, err
+//	}
+func Preconditions[ParamTypeT any, BodyTypeT any, ResponseBodyT any](
+	current func(ctx handler.HandlerContext[ParamTypeT, BodyTypeT], r *http.Request) (core.ResourceVersion, error),
+) func(next handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT]) handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+	return func(next handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT]) handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+		return func(ctx handler.HandlerContext[ParamTypeT, BodyTypeT], w http.ResponseWriter, r *http.Request) (ResponseBodyT, error) {
+			if core.HasPreconditions(r) {
+				version, err := current(ctx, r)
+				if err != nil {
+					var zeroResponse ResponseBodyT
+					return zeroResponse, err
+				}
+				if err := core.CheckPreconditions(r, version); err != nil {
+					var zeroResponse ResponseBodyT
+					return zeroResponse, err
+				}
+			}
+
+			return next(ctx, w, r)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package typed
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+type versionedDoc struct {
+	Title   string `json:"title"`
+	Version int    `json:"-"`
+}
+
+func (d versionedDoc) ResourceVersion() core.ResourceVersion {
+	return core.ResourceVersion{ETag: core.StrongETag("v" + strconv.Itoa(d.Version))}
+}
+
+// TestResponseETag verifies ETags are sent and matching If-None-Match yields 304
+func TestResponseETag(t *testing.T) {
+	serve := func(h handler.Handler[struct{}, struct{}, negotiatedUser], ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/users/1", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handlerCtx := handler.HandlerContext[struct{}, struct{}]{
+			Context: req.Context(),
+			Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+		if _, err := h(handlerCtx, rec, req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return rec
+	}
+
+	getUser := ResponseETag(func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (negotiatedUser, error) {
+		return negotiatedUser{Name: "Ada"}, nil
+	})
+
+	first := serve(getUser, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Body.Len() == 0 {
+		t.Fatalf("Expected 200 with ETag and body, got %d %q", first.Code, etag)
+	}
+
+	second := serve(getUser, `W/"other", `+etag)
+	if second.Code != http.StatusNotModified || second.Body.Len() != 0 {
+		t.Errorf("Expected empty 304, got %d with %q", second.Code, second.Body.String())
+	}
+	if second.Header().Get("ETag") != etag {
+		t.Errorf("Expected 304 to repeat the ETag")
+	}
+
+	weak := ResponseETagWith[struct{}, struct{}, negotiatedUser](core.ETagOptions{Weak: true})(
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (negotiatedUser, error) {
+			return negotiatedUser{Name: "Ada"}, nil
+		})
+	if got := serve(weak, "").Header().Get("ETag"); got != "W/"+etag {
+		t.Errorf("Expected weak ETag W/%s, got %s", etag, got)
+	}
+}
+
+// TestResponseETag_Versioned verifies handler-supplied versions take precedence over body hashes
+func TestResponseETag_Versioned(t *testing.T) {
+	getDoc := ResponseETag(func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (versionedDoc, error) {
+		return versionedDoc{Title: "Spec", Version: 3}, nil
+	})
+
+	req := httptest.NewRequest("GET", "/docs/1", nil)
+	rec := httptest.NewRecorder()
+	handlerCtx := handler.HandlerContext[struct{}, struct{}]{Context: req.Context(), Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	if _, err := getDoc(handlerCtx, rec, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := rec.Header().Get("ETag"); got != `"v3"` {
+		t.Errorf(`Expected ETag "v3", got %s`, got)
+	}
+}
+
+// TestPreconditions verifies stale writes are rejected with 412
+func TestPreconditions(t *testing.T) {
+	loads := 0
+	current := func(ctx handler.HandlerContext[struct{}, struct{}], r *http.Request) (core.ResourceVersion, error) {
+		loads++
+		return core.ResourceVersion{ETag: core.StrongETag("v2")}, nil
+	}
+	update := Preconditions[struct{}, struct{}, struct{}](current)(
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, nil
+		})
+
+	run := func(ifMatch string) error {
+		req := httptest.NewRequest("PUT", "/docs/1", nil)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		handlerCtx := handler.HandlerContext[struct{}, struct{}]{Context: req.Context(), Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+		_, err := update(handlerCtx, httptest.NewRecorder(), req)
+		return err
+	}
+
+	if err := run(""); err != nil || loads != 0 {
+		t.Errorf("Expected unconditional write to skip the version lookup, got err=%v loads=%d", err, loads)
+	}
+	if err := run(`"v2"`); err != nil {
+		t.Errorf("Expected matching If-Match to pass, got %v", err)
+	}
+
+	err := run(`"v1"`)
+	var apiErr *core.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412, got %v", err)
+	}
+
+	if err := run(`W/"v2"`); err == nil {
+		t.Error("Expected weak ETag to fail strong If-Match comparison")
+	}
+	if err := run("*"); err != nil {
+		t.Errorf("Expected If-Match: * to pass for an existing resource, got %v", err)
+	}
+
+	// A resource versioned only by modification time still exists for If-Match: *
+	unversioned := Preconditions[struct{}, struct{}, struct{}](
+		func(ctx handler.HandlerContext[struct{}, struct{}], r *http.Request) (core.ResourceVersion, error) {
+			return core.ResourceVersion{LastModified: time.Now()}, nil
+		})(
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, nil
+		})
+	req := httptest.NewRequest("PUT", "/docs/1", nil)
+	req.Header.Set("If-Match", "*")
+	handlerCtx := handler.HandlerContext[struct{}, struct{}]{Context: req.Context(), Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	if _, err := unversioned(handlerCtx, httptest.NewRecorder(), req); err != nil {
+		t.Errorf("Expected If-Match: * to pass for an existing resource without an ETag, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package typed
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+	httpMiddleware "github.com/platform-smith-labs/japi-core/v3/middleware/http"
+)
+
+// RequestIDHook is WithRequestID as a registry-wide hook: it sets ctx.RequestID from the
+// request ID of http.WithRequestID and adds it to the logger of every route.
+//
+// Use: reg.Use(typed.RequestIDHook, typed.LoggingHook)
+func RequestIDHook(next handler.HookHandler) handler.HookHandler {
+	return func(ctx handler.HookContext, w http.ResponseWriter, r *http.Request) (any, error) {
+		if requestID := httpMiddleware.GetRequestID(r); requestID != "" {
+			ctx.RequestID = handler.NewNullable(requestID)
+			ctx.Logger = ctx.Logger.With(slog.String("request_id", requestID))
+		}
+		return next(ctx, w, r)
+	}
+}
+
+// LoggingHook is WithLogging as a registry-wide hook: it logs each request and its outcome
+// with the route pattern and duration. Register it after RequestIDHook so entries carry the request ID.
+// Like every hook it runs inside the route's middleware, so requests rejected there (e.g. by
+// RequireAuth) are not logged; use http.WithLogging to log every request.
+//
+// Use: reg.Use(typed.RequestIDHook, typed.LoggingHook)
+func LoggingHook(next handler.HookHandler) handler.HookHandler {
+	return func(ctx handler.HookContext, w http.ResponseWriter, r *http.Request) (any, error) {
+		startTime := time.Now()
+		ctx.Logger.Info("HTTP Request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", ctx.Route.Path,
+			"remote_addr", r.RemoteAddr,
+			"user_agent", r.UserAgent(),
+		)
+
+		response, err := next(ctx, w, r)
+
+		duration := time.Since(startTime)
+		if err != nil {
+			ctx.Logger.Error("HTTP Response Error",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"route", ctx.Route.Path,
+				"error", err.Error(),
+				"duration_ms", duration.Milliseconds(),
+			)
+		} else {
+			ctx.Logger.Info("HTTP Response Success",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"route", ctx.Route.Path,
+				"duration_ms", duration.Milliseconds(),
+			)
+		}
+		return response, err
+	}
+}
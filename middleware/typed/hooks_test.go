@@ -0,0 +1,132 @@
+package typed
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+	"github.com/platform-smith-labs/japi-core/v3/jwt"
+)
+
+// TestHooksSeeAuthenticatedUser verifies hooks run after RequireAuth and see the authenticated user
+func TestHooksSeeAuthenticatedUser(t *testing.T) {
+	const secret = "test-secret"
+	userID, companyID := uuid.New(), uuid.New()
+	token, _, err := jwt.GenerateToken(userID, companyID, "user@example.com", secret, "test", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	var audited []uuid.UUID
+	audit := func(next handler.HookHandler) handler.HookHandler {
+		return func(ctx handler.HookContext, w http.ResponseWriter, r *http.Request) (any, error) {
+			if got := ctx.CompanyUUID.ValueOrDefault(); got != companyID {
+				t.Errorf("Expected the authenticated company before next, got %v", got)
+			}
+			response, err := next(ctx, w, r)
+			audited = append(audited, ctx.UserUUID.ValueOrDefault())
+			return response, err
+		}
+	}
+	requireAuth := func(next handler.Handler[struct{}, struct{}, string]) handler.Handler[struct{}, struct{}, string] {
+		return RequireAuth(secret, func(querier interface{}, userUUID, companyUUID uuid.UUID) error { return nil }, next)
+	}
+
+	reg := handler.NewRegistry()
+	reg.Use(audit)
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "GET", Path: "/me"},
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (string, error) {
+			return ctx.UserUUID.ValueOrDefault().String(), nil
+		},
+		requireAuth, ResponseJSON,
+	)
+
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	serve := func(authorization string) int {
+		req := httptest.NewRequest("GET", "/me", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve("Bearer " + token); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if len(audited) != 1 || audited[0] != userID {
+		t.Errorf("Expected the hook to log the authenticated user, got %v", audited)
+	}
+
+	if code := serve(""); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", code)
+	}
+	if len(audited) != 1 {
+		t.Errorf("Expected rejected requests not to reach the hook, got %v", audited)
+	}
+}
+
+// TestHooksSkipRejectedRequests verifies requests rejected by ParseParams or ParseBody never reach hooks
+func TestHooksSkipRejectedRequests(t *testing.T) {
+	type itemParams struct {
+		ID int `param:"id" validate:"required"`
+	}
+	type itemBody struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	var hooked []string
+	record := func(next handler.HookHandler) handler.HookHandler {
+		return func(ctx handler.HookContext, w http.ResponseWriter, r *http.Request) (any, error) {
+			hooked = append(hooked, r.URL.Path)
+			return next(ctx, w, r)
+		}
+	}
+
+	reg := handler.NewRegistry()
+	reg.Use(record)
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "PUT", Path: "/items/{id}"},
+		func(ctx handler.HandlerContext[itemParams, itemBody], w http.ResponseWriter, r *http.Request) (string, error) {
+			return ctx.Body.ValueOrDefault().Name, nil
+		},
+		ParseBody, ParseParams, ResponseJSON,
+	)
+
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	serve := func(path, body string) int {
+		req := httptest.NewRequest("PUT", path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve("/items/abc", `{"name":"a"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid params, got %d", code)
+	}
+	if code := serve("/items/1", `{"name":`); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid body, got %d", code)
+	}
+	if code := serve("/items/1", `{}`); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a body failing validation, got %d", code)
+	}
+	if len(hooked) != 0 {
+		t.Errorf("Expected rejected requests not to reach the hook, got %v", hooked)
+	}
+
+	if code := serve("/items/1", `{"name":"a"}`); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if len(hooked) != 1 || hooked[0] != "/items/1" {
+		t.Errorf("Expected the accepted request to reach the hook, got %v", hooked)
+	}
+}
@@ -0,0 +1,53 @@
+package typed
+
+import (
+	"net/http"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+// ResponsePage writes a paginated response with navigation links.
+//
+// The handler returns a core.Page[ItemT] built with core.OffsetPage or core.CursorPage.
+// This middleware resolves the first/prev/next/last links against the request URL
+// (preserving filter parameters), sets the RFC 8288 Link header, and writes
+// {"data": [...], "count": N, "total": T, "limit": L, "links": {...}} as JSON.
+//
+// Dependencies: core.WritePage
+// Context modifications: None
+// Use: Apply via MakeHandler(listHandler, ParseParams, ResponsePage)
+//
+// Example:
+//
+//	type ListUsersParams struct {
+//	    core.PageParams
+//	}
+//
+//	func ListUsers(ctx handler.HandlerContext[ListUsersParams, struct{}], w http.ResponseWriter, r *http.Request) (core.Page[User], error) {
+//	    params := ctx.Params.ValueOrDefault().PageParams
+//	    users, total, err := fetchUsers(ctx.Context, params.PageLimit(), params.Offset)
+//	    if err != nil {
+//	        return core.Page[User]{}, err
+//	    }
+//	    return core.OffsetPage(users, params, total), nil
+//	}
+//	handler := MakeHandler(reg, RouteInfo{Method: "GET", Path: "/api/v1/users"}, ListUsers, ParseParams, ResponsePage)
+func ResponsePage[ParamTypeT any, BodyTypeT any, ItemT any](next handler.Handler[ParamTypeT, BodyTypeT, core.Page[ItemT]]) handler.Handler[ParamTypeT, BodyTypeT, core.Page[ItemT]] {
+	return func(ctx handler.HandlerContext[ParamTypeT, BodyTypeT], w http.ResponseWriter, r *http.Request) (core.Page[ItemT], error) {
+		// Execute the handler
+		page, err := next(ctx, w, r)
+		if err != nil {
+			// Don't handle errors here - let the adapter handle them
+			return page, err
+		}
+
+		// Write the page with its navigation links
+		if err := core.WritePage(w, r, http.StatusOK, page); err != nil {
+			ctx.Logger.Error("Failed to write page response", "error", err.Error(), "path", r.URL.Path)
+			return page, core.NewAPIError(http.StatusInternalServerError, "Failed to write response")
+		}
+
+		return page, nil
+	}
+}
@@ -0,0 +1,148 @@
+package typed
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+type listItemsParams struct {
+	core.PageParams
+	Status string `query:"status"`
+}
+
+// TestResponsePage verifies page params parsing, page metadata and Link headers
+func TestResponsePage(t *testing.T) {
+	var gotParams listItemsParams
+	listHandler := func(ctx handler.HandlerContext[listItemsParams, struct{}], w http.ResponseWriter, r *http.Request) (core.Page[int], error) {
+		gotParams = ctx.Params.ValueOrDefault()
+		return core.OffsetPage([]int{21, 22, 23, 24, 25, 26, 27, 28, 29, 30}, gotParams.PageParams, 45), nil
+	}
+	wrappedHandler := ParseParams(ResponsePage(listHandler))
+
+	serve := func(target string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest("GET", target, nil)
+		rec := httptest.NewRecorder()
+		handlerCtx := handler.HandlerContext[listItemsParams, struct{}]{
+			Context: req.Context(),
+			Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+		_, err := wrappedHandler(handlerCtx, rec, req)
+		return rec, err
+	}
+
+	t.Run("offset page with links", func(t *testing.T) {
+		rec, err := serve("/items?status=open&limit=10&offset=20")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if gotParams.Limit != 10 || gotParams.Offset != 20 || gotParams.Status != "open" {
+			t.Errorf("Embedded page params not parsed: %+v", gotParams)
+		}
+
+		var page struct {
+			Count int            `json:"count"`
+			Total int            `json:"total"`
+			Limit int            `json:"limit"`
+			Links core.PageLinks `json:"links"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Failed to decode page: %v", err)
+		}
+		if page.Count != 10 || page.Total != 45 || page.Limit != 10 {
+			t.Errorf("Unexpected page metadata: %+v", page)
+		}
+		if page.Links.Next != "/items?limit=10&offset=30&status=open" {
+			t.Errorf("Unexpected next link: %q", page.Links.Next)
+		}
+		if page.Links.Prev != "/items?limit=10&offset=10&status=open" {
+			t.Errorf("Unexpected prev link: %q", page.Links.Prev)
+		}
+		if page.Links.Last != "/items?limit=10&offset=40&status=open" {
+			t.Errorf("Unexpected last link: %q", page.Links.Last)
+		}
+
+		link := rec.Header().Get("Link")
+		if !strings.Contains(link, `</items?limit=10&offset=30&status=open>; rel="next"`) {
+			t.Errorf("Link header missing next relation: %q", link)
+		}
+	})
+
+	t.Run("caps limits above the maximum", func(t *testing.T) {
+		rec, err := serve("/items?limit=1000")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var page struct {
+			Limit int `json:"limit"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Failed to decode page: %v", err)
+		}
+		if gotParams.PageLimit() != core.MaxPageLimit || page.Limit != core.MaxPageLimit {
+			t.Errorf("Expected the limit to be capped at %d, got %d", core.MaxPageLimit, page.Limit)
+		}
+	})
+
+	t.Run("rejects non-positive limits", func(t *testing.T) {
+		_, err := serve("/items?limit=-1")
+		var apiErr *core.APIError
+		if !errors.As(err, &apiErr) || apiErr.Fields["limit"] == "" {
+			t.Errorf("Expected validation error on limit, got %v", err)
+		}
+	})
+}
+
+// TestResponsePageKeepsSuccessorLink verifies page links are added next to the successor link of a deprecated route
+func TestResponsePageKeepsSuccessorLink(t *testing.T) {
+	reg := handler.NewRegistry()
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "GET", Path: "/v1/items", Deprecated: true, Successor: "/v2/items"},
+		func(ctx handler.HandlerContext[listItemsParams, struct{}], w http.ResponseWriter, r *http.Request) (core.Page[int], error) {
+			return core.OffsetPage([]int{1, 2}, ctx.Params.ValueOrDefault().PageParams, 10), nil
+		},
+		ResponsePage, ParseParams,
+	)
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/items?limit=2", nil))
+	links := strings.Join(rec.Header().Values("Link"), ", ")
+	if !strings.Contains(links, `</v2/items>; rel="successor-version"`) || !strings.Contains(links, `rel="next"`) {
+		t.Errorf("Expected successor and page links, got %q", links)
+	}
+}
+
+// TestCursorCodec verifies cursors round-trip and reject tampering
+func TestCursorCodec(t *testing.T) {
+	type position struct {
+		ID int `json:"id"`
+	}
+	codec := core.NewCursorCodec([]byte("0123456789abcdef0123456789abcdef"))
+
+	cursor, err := codec.Encode(position{ID: 42})
+	if err != nil {
+		t.Fatalf("Failed to encode cursor: %v", err)
+	}
+
+	var decoded position
+	if err := codec.Decode(cursor, &decoded); err != nil || decoded.ID != 42 {
+		t.Errorf("Expected round trip to ID 42, got %+v (%v)", decoded, err)
+	}
+
+	forged, _ := core.NewCursorCodec([]byte("another-secret")).Encode(position{ID: 1})
+	err = codec.Decode(forged, &decoded)
+	var apiErr *core.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest || apiErr.ErrorCode != "pagination.invalid_cursor" {
+		t.Errorf("Expected invalid cursor error, got %v", err)
+	}
+}
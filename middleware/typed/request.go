@@ -52,12 +52,12 @@ func ParseParams[ParamTypeT any, BodyTypeT any, ResponseBodyT any](next handler.
 
 		var params ParamTypeT
 		val := reflect.ValueOf(&params).Elem()
-		typ := val.Type()
 
 		// Extract URL path parameters and query parameters based on struct tags
-		for i := 0; i < val.NumField(); i++ {
-			field := val.Field(i)
-			fieldType := typ.Field(i)
+		// (including fields promoted from embedded structs such as core.PageParams)
+		for _, pf := range paramFields(val) {
+			field := pf.value
+			fieldType := pf.field
 
 			paramTag := fieldType.Tag.Get("param")
 			queryTag := fieldType.Tag.Get("query")
@@ -104,12 +104,7 @@ func ParseParams[ParamTypeT any, BodyTypeT any, ResponseBodyT any](next handler.
 		// Validate the populated struct
 		if err := validate.Struct(params); err != nil {
 			var zeroResponse ResponseBodyT
-			fieldErrors := parseValidationErrors(err)
-			validationErr := core.NewValidationError("Parameter validation failed")
-			for field, errors := range fieldErrors {
-				validationErr.AddField(field, strings.Join(errors, " || "))
-			}
-			return zeroResponse, validationErr
+			return zeroResponse, newValidationError(core.LocalizerFor(r), err, reflect.TypeOf(params), core.MessageParamValidationFailed, "Parameter validation failed")
 		}
 
 		// Set validated parameters in context
@@ -179,12 +174,7 @@ func ParseBody[ParamTypeT any, BodyTypeT any, ResponseBodyT any](next handler.Ha
 		// Validate body structure
 		if err := validate.Struct(body); err != nil {
 			var zeroResponse ResponseBodyT
-			fieldErrors := parseValidationErrors(err)
-			validationErr := core.NewValidationError("Validation failed")
-			for field, errors := range fieldErrors {
-				validationErr.AddField(field, strings.Join(errors, " || "))
-			}
-			return zeroResponse, validationErr
+			return zeroResponse, newValidationError(core.LocalizerFor(r), err, reflect.TypeOf(body), core.MessageValidationFailed, "Validation failed")
 		}
 
 		// Set validated body in context
@@ -218,6 +208,31 @@ func ParseHeaders[ParamTypeT any, BodyTypeT any, ResponseBodyT any](next handler
 
 // Helper functions
 
+// paramField is a settable struct field that may carry a param or query tag
+type paramField struct {
+	value reflect.Value
+	field reflect.StructField
+}
+
+// paramFields lists the fields of a params struct, flattening untagged embedded structs
+// so their param/query fields are promoted like Go promotes embedded fields
+func paramFields(val reflect.Value) []paramField {
+	var fields []paramField
+	typ := val.Type()
+
+	for i := 0; i < val.NumField(); i++ {
+		fieldType := typ.Field(i)
+		if fieldType.Anonymous && fieldType.Type.Kind() == reflect.Struct &&
+			fieldType.Tag.Get("param") == "" && fieldType.Tag.Get("query") == "" {
+			fields = append(fields, paramFields(val.Field(i))...)
+			continue
+		}
+		fields = append(fields, paramField{value: val.Field(i), field: fieldType})
+	}
+
+	return fields
+}
+
 // isRequired checks if a field is marked as required in validation tags
 func isRequired(field reflect.StructField) bool {
 	validateTag := field.Tag.Get("validate")
@@ -297,33 +312,126 @@ func setFieldValue(field reflect.Value, value string) error {
 	return nil
 }
 
-// parseValidationErrors converts validator.ValidationErrors to a structured field map
-func parseValidationErrors(err error) map[string][]string {
-	fieldErrors := make(map[string][]string)
+// newValidationError converts validator errors into a 400 APIError with one core.FieldError per
+// failed rule, located by JSON pointer, and messages rendered in the localizer's language.
+// Fields keeps the flat rendering: top-level fields under their name, nested values under a
+// dotted path such as "owner.email" or "items[3].email". root is the validated struct's type.
+func newValidationError(localizer core.Localizer, err error, root reflect.Type, messageKey, fallback string) *core.APIError {
+	validationErr := core.NewValidationError(localizedMessage(localizer, messageKey, fallback))
 
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		for _, fieldError := range validationErrors {
-			// Convert field name to lowercase for consistent JSON keys
-			fieldName := strings.ToLower(fieldError.Field())
+			path := namespacePath(root, fieldError.StructNamespace())
+			validationErr.AddFieldError(fieldKey(path), core.FieldError{
+				Path:    jsonPointer(path),
+				Field:   fieldError.Field(),
+				Tag:     fieldError.Tag(),
+				Param:   fieldError.Param(),
+				Message: generateFieldErrorMessage(localizer, fieldError),
+			})
+		}
+	}
 
-			// Remove struct name prefix if present (e.g., "CreateUserRequest.Password" -> "password")
-			if dotIndex := strings.LastIndex(fieldName, "."); dotIndex != -1 {
-				fieldName = fieldName[dotIndex+1:]
-			}
+	return validationErr
+}
+
+// pathSegment is one step of a validated value's location: a field name or a slice index/map key
+type pathSegment struct {
+	name    string
+	indexed bool
+}
 
-			// Generate user-friendly error message
-			message := generateFieldErrorMessage(fieldError)
+// namespacePath resolves a validator struct namespace such as "CreateOrder.Items[3].Email"
+// against root into JSON segments ("items", "3", "email"). Untagged embedded structs are
+// skipped, since JSON flattens their fields into the parent.
+func namespacePath(root reflect.Type, namespace string) []pathSegment {
+	if dotIndex := strings.Index(namespace, "."); dotIndex != -1 {
+		namespace = namespace[dotIndex+1:]
+	}
 
-			// Append error message to field (supports multiple errors per field)
-			fieldErrors[fieldName] = append(fieldErrors[fieldName], message)
+	var path []pathSegment
+	current := root
+	for namespace != "" {
+		for current != nil && current.Kind() == reflect.Pointer {
+			current = current.Elem()
+		}
+
+		switch namespace[0] {
+		case '.':
+			namespace = namespace[1:]
+		case '[':
+			end := strings.Index(namespace, "]")
+			if end == -1 {
+				end = len(namespace)
+			}
+			path = append(path, pathSegment{name: namespace[1:end], indexed: true})
+			namespace = namespace[min(end+1, len(namespace)):]
+			if current != nil && (current.Kind() == reflect.Slice || current.Kind() == reflect.Array || current.Kind() == reflect.Map) {
+				current = current.Elem()
+			} else {
+				current = nil
+			}
+		default:
+			end := strings.IndexAny(namespace, ".[")
+			if end == -1 {
+				end = len(namespace)
+			}
+			name := namespace[:end]
+			namespace = namespace[end:]
+
+			if current == nil || current.Kind() != reflect.Struct {
+				path = append(path, pathSegment{name: name})
+				continue
+			}
+			field, ok := current.FieldByName(name)
+			if !ok {
+				path = append(path, pathSegment{name: name})
+				current = nil
+				continue
+			}
+			current = field.Type
+			if field.Anonymous && jsonTagName(field) == "" {
+				continue
+			}
+			path = append(path, pathSegment{name: fieldName(field)})
 		}
 	}
+	return path
+}
 
-	return fieldErrors
+// jsonPointer renders path as an RFC 6901 JSON pointer, e.g. "/items/3/email"
+func jsonPointer(path []pathSegment) string {
+	var pointer strings.Builder
+	for _, segment := range path {
+		pointer.WriteString("/")
+		pointer.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(segment.name))
+	}
+	return pointer.String()
+}
+
+// fieldKey renders path as an APIError.Fields key, e.g. "items[3].email".
+// Field names are lower-cased as they always have been for Fields.
+func fieldKey(path []pathSegment) string {
+	var key strings.Builder
+	for i, segment := range path {
+		switch {
+		case segment.indexed:
+			key.WriteString("[" + segment.name + "]")
+		case i > 0:
+			key.WriteString("." + strings.ToLower(segment.name))
+		default:
+			key.WriteString(strings.ToLower(segment.name))
+		}
+	}
+	return key.String()
 }
 
-// generateFieldErrorMessage converts validator field error to user-friendly message
-func generateFieldErrorMessage(fieldError validator.FieldError) string {
+// generateFieldErrorMessage converts validator field error to user-friendly message.
+//
+// Templates are looked up in the request's message catalog as "validation.<tag>", preferring
+// "validation.<tag>.string" for string fields, then "validation.default".
+// Placeholders: {field} (struct field name), {param} (tag parameter), {tag} (validation tag).
+func generateFieldErrorMessage(localizer core.Localizer, fieldError validator.FieldError) string {
 	fieldName := fieldError.Field()
 	tag := fieldError.Tag()
 	param := fieldError.Param()
@@ -333,29 +441,24 @@ func generateFieldErrorMessage(fieldError validator.FieldError) string {
 		fieldName = fieldName[dotIndex+1:]
 	}
 
-	switch tag {
-	case "required":
-		return fmt.Sprintf("%s is required", fieldName)
-	case "min":
-		if fieldError.Kind().String() == "string" {
-			return fmt.Sprintf("%s must be at least %s characters", fieldName, param)
-		}
-		return fmt.Sprintf("%s must be at least %s", fieldName, param)
-	case "max":
-		if fieldError.Kind().String() == "string" {
-			return fmt.Sprintf("%s must be at most %s characters", fieldName, param)
-		}
-		return fmt.Sprintf("%s must be at most %s", fieldName, param)
-	case "email":
-		return fmt.Sprintf("%s must be a valid email address", fieldName)
-	case "uuid":
-		return fmt.Sprintf("%s must be a valid UUID", fieldName)
-	case "url":
-		return fmt.Sprintf("%s must be a valid URL", fieldName)
-	case "eqfield":
-		return fmt.Sprintf("%s must match %s", fieldName, param)
-	default:
-		// Fallback for unknown tags
-		return fmt.Sprintf("%s validation failed on '%s' tag", fieldName, tag)
+	keys := []string{"validation." + tag, core.MessageValidationFieldDefault}
+	if fieldError.Kind() == reflect.String {
+		keys = append([]string{"validation." + tag + ".string"}, keys...)
+	}
+
+	args := map[string]string{"field": fieldName, "param": param, "tag": tag}
+	if message, ok := localizer.Format(args, keys...); ok {
+		return message
+	}
+
+	// Catalog without any validation templates
+	return fmt.Sprintf("%s validation failed on '%s' tag", fieldName, tag)
+}
+
+// localizedMessage renders key in the localizer's language, or returns fallback if the catalog lacks it
+func localizedMessage(localizer core.Localizer, key, fallback string) string {
+	if message, ok := localizer.Format(nil, key); ok {
+		return message
 	}
+	return fallback
 }
@@ -0,0 +1,205 @@
+package typed
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+type localizedSignup struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+// TestParseBody_LocalizedMessages verifies validation messages follow Accept-Language
+func TestParseBody_LocalizedMessages(t *testing.T) {
+	messages := core.NewMessageCatalog("en").
+		Register("en", map[string]string{
+			core.MessageValidationFailed: "Validation failed",
+			"validation.required":        "{field} is required",
+			"validation.min.string":      "{field} must be at least {param} characters",
+		}).
+		Register("de", map[string]string{
+			core.MessageValidationFailed: "Validierung fehlgeschlagen",
+			"validation.min.string":      "{field} muss mindestens {param} Zeichen lang sein",
+		})
+
+	wrappedHandler := ParseBody(func(ctx handler.HandlerContext[struct{}, localizedSignup], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		return struct{}{}, nil
+	})
+
+	validate := func(acceptLanguage string) *core.APIError {
+		req := httptest.NewRequest("POST", "/signup", bytes.NewBufferString(`{"password":"short"}`))
+		req.Header.Set("Accept-Language", acceptLanguage)
+		req = req.WithContext(core.ContextWithMessageCatalog(req.Context(), messages))
+		handlerCtx := handler.HandlerContext[struct{}, localizedSignup]{
+			Context: req.Context(),
+			Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+
+		_, err := wrappedHandler(handlerCtx, httptest.NewRecorder(), req)
+		var apiErr *core.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %v", err)
+		}
+		return apiErr
+	}
+
+	t.Run("uses the negotiated locale", func(t *testing.T) {
+		apiErr := validate("de-CH, en;q=0.5")
+		if apiErr.Message != "Validierung fehlgeschlagen" {
+			t.Errorf("Expected German message, got %q", apiErr.Message)
+		}
+		if got := apiErr.Fields["password"]; got != "password muss mindestens 8 Zeichen lang sein" {
+			t.Errorf("Expected German field message, got %q", got)
+		}
+		// Missing German template falls back to English
+		if got := apiErr.Fields["email"]; got != "email is required" {
+			t.Errorf("Expected English fallback, got %q", got)
+		}
+	})
+
+	t.Run("falls back for unsupported languages", func(t *testing.T) {
+		apiErr := validate("fr")
+		if apiErr.Message != "Validation failed" {
+			t.Errorf("Expected English message, got %q", apiErr.Message)
+		}
+		if got := apiErr.Fields["password"]; got != "password must be at least 8 characters" {
+			t.Errorf("Expected English field message, got %q", got)
+		}
+	})
+}
+
+// TestParseBody_CatalogKeepsBuiltinMessages verifies a catalog without English templates keeps the built-in messages
+func TestParseBody_CatalogKeepsBuiltinMessages(t *testing.T) {
+	messages := core.NewMessageCatalog("en").Register("de", map[string]string{
+		"validation.required": "{field} ist erforderlich",
+	})
+	wrappedHandler := ParseBody(func(ctx handler.HandlerContext[struct{}, localizedSignup], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		return struct{}{}, nil
+	})
+
+	validate := func(acceptLanguage string) *core.APIError {
+		req := httptest.NewRequest("POST", "/signup", bytes.NewBufferString(`{"password":"short"}`))
+		req.Header.Set("Accept-Language", acceptLanguage)
+		req = req.WithContext(core.ContextWithMessageCatalog(req.Context(), messages))
+		handlerCtx := handler.HandlerContext[struct{}, localizedSignup]{
+			Context: req.Context(),
+			Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+		_, err := wrappedHandler(handlerCtx, httptest.NewRecorder(), req)
+		var apiErr *core.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %v", err)
+		}
+		return apiErr
+	}
+
+	apiErr := validate("fr")
+	if apiErr.Message != "Validation failed" || apiErr.Fields["email"] != "email is required" ||
+		apiErr.Fields["password"] != "password must be at least 8 characters" {
+		t.Errorf("Expected the built-in English messages, got %q %v", apiErr.Message, apiErr.Fields)
+	}
+
+	apiErr = validate("de")
+	if apiErr.Fields["email"] != "email ist erforderlich" || apiErr.Fields["password"] != "password must be at least 8 characters" {
+		t.Errorf("Expected German overrides with built-in fallbacks, got %v", apiErr.Fields)
+	}
+}
+
+// TestWriteAPIError_LocalizedErrorCode verifies coded errors take their message from the catalog
+func TestWriteAPIError_LocalizedErrorCode(t *testing.T) {
+	messages := core.NewMessageCatalog("en").Register("de", map[string]string{
+		"user.email_taken": "E-Mail-Adresse ist bereits registriert",
+	})
+
+	req := httptest.NewRequest("POST", "/users", nil)
+	req.Header.Set("Accept-Language", "de")
+	req = req.WithContext(core.ContextWithMessageCatalog(req.Context(), messages))
+	rec := httptest.NewRecorder()
+
+	core.WriteAPIError(rec, req, *core.NewAPIError(http.StatusConflict, "Email already registered").WithCode("user.email_taken"))
+
+	if !bytes.Contains(rec.Body.Bytes(), []byte("E-Mail-Adresse ist bereits registriert")) {
+		t.Errorf("Expected localized message, got %s", rec.Body.String())
+	}
+	if lang := rec.Header().Get("Content-Language"); lang != "de" {
+		t.Errorf("Expected Content-Language de, got %q", lang)
+	}
+}
+
+type orderOwner struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type orderItem struct {
+	SKU   string `json:"sku" validate:"required"`
+	Email string `json:"email" validate:"omitempty,email"`
+}
+
+type createOrder struct {
+	Email  string            `json:"email" validate:"required,email"`
+	Owner  orderOwner        `json:"owner"`
+	Items  []orderItem       `json:"items" validate:"min=1,dive"`
+	Labels map[string]string `json:"labels" validate:"dive,max=5"`
+}
+
+// TestParseBody_StructuredErrors verifies nested validation errors keep distinct, path-aware locations
+func TestParseBody_StructuredErrors(t *testing.T) {
+	wrappedHandler := ParseBody(func(ctx handler.HandlerContext[struct{}, createOrder], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		return struct{}{}, nil
+	})
+
+	body := `{"email":"a@example.com","owner":{"email":"nope"},"items":[{"sku":"A"},{"sku":"","email":"bad"}],"labels":{"tier":"platinum"}}`
+	req := httptest.NewRequest("POST", "/orders", bytes.NewBufferString(body))
+	handlerCtx := handler.HandlerContext[struct{}, createOrder]{
+		Context: req.Context(),
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	_, err := wrappedHandler(handlerCtx, httptest.NewRecorder(), req)
+	var apiErr *core.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+
+	byPath := make(map[string]core.FieldError)
+	for _, fieldError := range apiErr.Errors {
+		byPath[fieldError.Path] = fieldError
+	}
+
+	want := map[string]string{
+		"/owner/email":   "email",
+		"/items/1/sku":   "required",
+		"/items/1/email": "email",
+		"/labels/tier":   "max",
+	}
+	if len(byPath) != len(want) {
+		t.Errorf("Expected %d errors, got %+v", len(want), apiErr.Errors)
+	}
+	for path, tag := range want {
+		if got, ok := byPath[path]; !ok || got.Tag != tag || got.Message == "" {
+			t.Errorf("Expected %s error at %s, got %+v", tag, path, got)
+		}
+	}
+	if got := byPath["/labels/tier"].Param; got != "5" {
+		t.Errorf("Expected param 5, got %q", got)
+	}
+
+	// Flat rendering no longer collapses distinct emails into one key
+	for _, key := range []string{"owner.email", "items[1].email", "items[1].sku", "labels[tier]"} {
+		if apiErr.Fields[key] == "" {
+			t.Errorf("Expected Fields[%q], got %v", key, apiErr.Fields)
+		}
+	}
+	if _, ok := apiErr.Fields["email"]; ok {
+		t.Errorf("Unexpected collapsed email key: %v", apiErr.Fields)
+	}
+}
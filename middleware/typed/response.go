@@ -2,6 +2,7 @@ package typed
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/platform-smith-labs/japi-core/v3/core"
 	"github.com/platform-smith-labs/japi-core/v3/handler"
@@ -11,7 +12,8 @@ import (
 //
 // This middleware should be the first in the chain (executes last) to handle response formatting.
 // It automatically determines the appropriate status code based on the HTTP method
-// (201 for POST, 200 for others) and writes the response as JSON.
+// (201 for POST, 200 for others) and writes the response as JSON. Handlers returning a
+// handler.Response choose the status, headers and cookies themselves; 204 and 304 are sent without a body.
 //
 // Dependencies: core.JSON
 // Context modifications: None
@@ -29,17 +31,15 @@ func ResponseJSON[ParamTypeT any, BodyTypeT any, ResponseBodyT any](next handler
 			return responseData, err
 		}
 
-		// Determine appropriate status code based on HTTP method
-		var statusCode int
-		switch r.Method {
-		case "POST":
-			statusCode = 201 // Created
-		default:
-			statusCode = 200 // OK
+		// Apply handler.Response metadata, defaulting the status from the HTTP method
+		statusCode, body := prepareResponse(w, r, responseData)
+		if !bodyAllowed(statusCode) {
+			w.WriteHeader(statusCode)
+			return responseData, nil
 		}
 
 		// Write successful JSON response
-		if err := core.JSON(w, statusCode, responseData); err != nil {
+		if err := core.JSON(w, statusCode, body); err != nil {
 			ctx.Logger.Error("Failed to write JSON response", "error", err.Error(), "path", r.URL.Path)
 			return responseData, core.NewAPIError(http.StatusInternalServerError, "Failed to write response")
 		}
@@ -70,17 +70,15 @@ func ResponseJSONFile[ParamTypeT any, BodyTypeT any, ResponseBodyT any](filename
 				return responseData, err
 			}
 
-			// Determine appropriate status code based on HTTP method
-			var statusCode int
-			switch r.Method {
-			case "POST":
-				statusCode = 201 // Created
-			default:
-				statusCode = 200 // OK
+			// Apply handler.Response metadata, defaulting the status from the HTTP method
+			statusCode, body := prepareResponse(w, r, responseData)
+			if !bodyAllowed(statusCode) {
+				w.WriteHeader(statusCode)
+				return responseData, nil
 			}
 
 			// Write successful JSON file response
-			if err := core.JSONFile(w, statusCode, responseData, filename); err != nil {
+			if err := core.JSONFile(w, statusCode, body, filename); err != nil {
 				ctx.Logger.Error("Failed to write JSON file response", "error", err.Error(), "path", r.URL.Path, "filename", filename)
 				return responseData, core.NewAPIError(http.StatusInternalServerError, "Failed to write response")
 			}
@@ -89,3 +87,100 @@ func ResponseJSONFile[ParamTypeT any, BodyTypeT any, ResponseBodyT any](filename
 		}
 	}
 }
+
+// ResponseNegotiated handles writing successful responses in the media type requested by the client.
+//
+// This middleware is a drop-in alternative to ResponseJSON. It selects an encoder from the
+// Accept header using core.DefaultEncoders (JSON, XML, MessagePack, CBOR), falling back to
+// JSON when no Accept header is sent, and returns 406 Not Acceptable when nothing matches or the
+// negotiated encoder cannot represent the response (XML has no encoding for maps).
+// Status codes follow ResponseJSON (201 for POST, 200 for others, or a handler.Response's Status).
+//
+// Dependencies: core.Negotiated, core.DefaultEncoders
+// Context modifications: None
+// Use: Apply via MakeHandler(myHandler, ParseParams, ResponseNegotiated)
+//
+// Example:
+//
+//	handler := MakeHandler(reg, RouteInfo{Method: "GET", Path: "/api/v1/users"}, listUsers, ResponseNegotiated)
+func ResponseNegotiated[ParamTypeT any, BodyTypeT any, ResponseBodyT any](next handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT]) handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+	return ResponseNegotiatedWith[ParamTypeT, BodyTypeT, ResponseBodyT](core.DefaultEncoders)(next)
+}
+
+// ResponseNegotiatedWith is like ResponseNegotiated but negotiates against a custom encoder registry.
+// Set RouteInfo.Produces so the swagger generator lists the registry's media types.
+//
+// Example:
+//
+//	encoders := core.NewEncoderRegistry(core.JSONEncoder, core.MessagePackEncoder)
+//	handler := MakeHandler(reg, RouteInfo{Method: "GET", Path: "/api/v1/users", Produces: encoders.MediaTypes()},
+//	    listUsers, ResponseNegotiatedWith[ListParams, struct{}, []User](encoders))
+func ResponseNegotiatedWith[ParamTypeT any, BodyTypeT any, ResponseBodyT any](encoders *core.EncoderRegistry) func(next handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT]) handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+	return func(next handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT]) handler.Handler[ParamTypeT, BodyTypeT, ResponseBodyT] {
+		return func(ctx handler.HandlerContext[ParamTypeT, BodyTypeT], w http.ResponseWriter, r *http.Request) (ResponseBodyT, error) {
+			// Reject unacceptable requests before running the handler
+			if _, ok := encoders.Negotiate(r.Header.Get("Accept")); !ok {
+				var zeroResponse ResponseBodyT
+				return zeroResponse, core.NewAPIError(http.StatusNotAcceptable, "Not Acceptable",
+					"Supported media types: "+strings.Join(encoders.MediaTypes(), ", "))
+			}
+
+			// Execute the handler
+			responseData, err := next(ctx, w, r)
+			if err != nil {
+				// Don't handle errors here - let the adapter handle them
+				return responseData, err
+			}
+
+			// Apply handler.Response metadata, defaulting the status from the HTTP method
+			statusCode, body := prepareResponse(w, r, responseData)
+			if !bodyAllowed(statusCode) {
+				w.WriteHeader(statusCode)
+				return responseData, nil
+			}
+
+			// Write successful response in the negotiated media type
+			if err := core.Negotiated(w, r, statusCode, body, encoders); err != nil {
+				if apiErr, ok := core.AsAPIError(err); ok {
+					return responseData, apiErr
+				}
+				ctx.Logger.Error("Failed to write negotiated response", "error", err.Error(), "path", r.URL.Path)
+				return responseData, core.NewAPIError(http.StatusInternalServerError, "Failed to write response")
+			}
+
+			return responseData, nil
+		}
+	}
+}
+
+// prepareResponse applies the headers and cookies of a handler.Response and returns the status
+// code and body to write. Other responses get the method default: 201 for POST, 200 otherwise.
+func prepareResponse(w http.ResponseWriter, r *http.Request, responseData any) (int, any) {
+	statusCode := http.StatusOK
+	if r.Method == http.MethodPost {
+		statusCode = http.StatusCreated
+	}
+
+	meta, ok := responseData.(handler.ResponseMetadata)
+	if !ok {
+		return statusCode, responseData
+	}
+
+	for key, values := range meta.ResponseHeaders() {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	for _, cookie := range meta.ResponseCookies() {
+		http.SetCookie(w, cookie)
+	}
+	if status := meta.ResponseStatus(); status != 0 {
+		statusCode = status
+	}
+	return statusCode, meta.ResponseBody()
+}
+
+// bodyAllowed reports whether a response with the given status may carry a body
+func bodyAllowed(status int) bool {
+	return status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -0,0 +1,197 @@
+package typed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type negotiatedUser struct {
+	Name string `json:"name" xml:"name" msgpack:"name" cbor:"name"`
+}
+
+// TestResponseNegotiated verifies the encoder is selected from the Accept header
+func TestResponseNegotiated(t *testing.T) {
+	testHandler := func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (negotiatedUser, error) {
+		return negotiatedUser{Name: "Ada"}, nil
+	}
+	wrappedHandler := ResponseNegotiated(testHandler)
+
+	serve := func(accept string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest("GET", "/users/1", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		handlerCtx := handler.HandlerContext[struct{}, struct{}]{
+			Context: req.Context(),
+			Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+		_, err := wrappedHandler(handlerCtx, rec, req)
+		return rec, err
+	}
+
+	t.Run("defaults to JSON without Accept header", func(t *testing.T) {
+		rec, err := serve("")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected application/json, got %s", ct)
+		}
+		var user negotiatedUser
+		if err := json.Unmarshal(rec.Body.Bytes(), &user); err != nil || user.Name != "Ada" {
+			t.Errorf("Expected JSON body with name Ada, got %s (%v)", rec.Body.String(), err)
+		}
+	})
+
+	t.Run("honours quality values", func(t *testing.T) {
+		rec, err := serve("application/json;q=0.5, application/msgpack")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/msgpack" {
+			t.Fatalf("Expected application/msgpack, got %s", ct)
+		}
+		var user negotiatedUser
+		if err := msgpack.Unmarshal(rec.Body.Bytes(), &user); err != nil || user.Name != "Ada" {
+			t.Errorf("Expected msgpack body with name Ada (%v)", err)
+		}
+	})
+
+	t.Run("matches wildcard ranges", func(t *testing.T) {
+		rec, err := serve("text/html, application/*;q=0.9")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected application/json, got %s", ct)
+		}
+	})
+
+	t.Run("returns 406 when nothing matches", func(t *testing.T) {
+		rec, err := serve("text/html")
+		var apiErr *core.APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusNotAcceptable {
+			t.Fatalf("Expected 406 APIError, got %v", err)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("Expected nothing written, got %s", rec.Body.String())
+		}
+	})
+}
+
+// TestResponseNegotiatedXML verifies slices get a root element and maps are refused with 406
+func TestResponseNegotiatedXML(t *testing.T) {
+	serve := func(wrapped handler.Handler[struct{}, struct{}, any]) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest("GET", "/users", nil)
+		req.Header.Set("Accept", "application/xml")
+		rec := httptest.NewRecorder()
+		handlerCtx := handler.HandlerContext[struct{}, struct{}]{
+			Context: req.Context(),
+			Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+		_, err := wrapped(handlerCtx, rec, req)
+		return rec, err
+	}
+	returning := func(response any) handler.Handler[struct{}, struct{}, any] {
+		return ResponseNegotiated(func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (any, error) {
+			return response, nil
+		})
+	}
+
+	t.Run("wraps slices in a root element", func(t *testing.T) {
+		rec, err := serve(returning([]negotiatedUser{{Name: "Ada"}, {Name: "Grace"}}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var list struct {
+			XMLName xml.Name         `xml:"items"`
+			Items   []negotiatedUser `xml:"item"`
+		}
+		if err := xml.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Items) != 2 || list.Items[1].Name != "Grace" {
+			t.Errorf("Expected a well-formed <items> document, got %s (%v)", rec.Body.String(), err)
+		}
+	})
+
+	t.Run("refuses maps with 406", func(t *testing.T) {
+		rec, err := serve(returning(map[string]int{"users": 2}))
+		var apiErr *core.APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusNotAcceptable {
+			t.Fatalf("Expected 406 APIError, got %v", err)
+		}
+		if rec.Code != http.StatusOK || rec.Body.Len() != 0 || rec.Header().Get("Content-Type") != "" {
+			t.Errorf("Expected nothing written, got %d %q", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+// TestResponseJSON_ResponseMetadata verifies handler.Response controls status, headers and cookies
+func TestResponseJSON_ResponseMetadata(t *testing.T) {
+	serve := func(method string, h handler.Handler[struct{}, struct{}, handler.Response[negotiatedUser]]) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/users", nil)
+		rec := httptest.NewRecorder()
+		handlerCtx := handler.HandlerContext[struct{}, struct{}]{
+			Context: req.Context(),
+			Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+		if _, err := ResponseJSON(h)(handlerCtx, rec, req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return rec
+	}
+
+	t.Run("defaults the status from the method", func(t *testing.T) {
+		rec := serve("POST", func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (handler.Response[negotiatedUser], error) {
+			return handler.NewResponse(negotiatedUser{Name: "Ada"}), nil
+		})
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Expected 201, got %d", rec.Code)
+		}
+		var user negotiatedUser
+		if err := json.Unmarshal(rec.Body.Bytes(), &user); err != nil || user.Name != "Ada" {
+			t.Errorf("Expected the unwrapped body, got %s (%v)", rec.Body.String(), err)
+		}
+	})
+
+	t.Run("applies status, headers and cookies", func(t *testing.T) {
+		rec := serve("POST", func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (handler.Response[negotiatedUser], error) {
+			return handler.Accepted(negotiatedUser{Name: "Ada"}).
+				WithHeader("Location", "/jobs/1").
+				WithCookie(&http.Cookie{Name: "job", Value: "1"}), nil
+		})
+		if rec.Code != http.StatusAccepted {
+			t.Errorf("Expected 202, got %d", rec.Code)
+		}
+		if location := rec.Header().Get("Location"); location != "/jobs/1" {
+			t.Errorf("Expected Location /jobs/1, got %q", location)
+		}
+		if cookie := rec.Header().Get("Set-Cookie"); cookie != "job=1" {
+			t.Errorf("Expected Set-Cookie job=1, got %q", cookie)
+		}
+	})
+
+	t.Run("omits the body for 204", func(t *testing.T) {
+		rec := serve("DELETE", func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (handler.Response[negotiatedUser], error) {
+			return handler.NoContent[negotiatedUser](), nil
+		})
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Expected 204, got %d", rec.Code)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("Expected no body, got %s", rec.Body.String())
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "" {
+			t.Errorf("Expected no Content-Type, got %s", ct)
+		}
+	})
+}
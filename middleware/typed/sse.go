@@ -0,0 +1,74 @@
+package typed
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+// ResponseSSE serves a Server-Sent Events stream returned by the handler.
+//
+// The handler runs the usual pipeline (auth, params, ...) and returns a core.SSEStream[EventT].
+// This middleware then writes text/event-stream with core.DefaultSSEOptions: events are flushed
+// as they are emitted, heartbeats keep idle connections open, the Last-Event-ID header is passed
+// to the stream for resume, and the stream's context is cancelled when the client disconnects.
+//
+// Errors returned by the handler itself produce a normal error response. Errors returned by the
+// stream after it started are sent as an "error" event and logged here, not returned.
+//
+// Dependencies: core.ServeSSE
+// Context modifications: None
+// Use: Apply via MakeHandler(eventsHandler, RequireAuth(...), ResponseSSE)
+//
+// Example:
+//
+//	func Notifications(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (core.SSEStream[Notification], error) {
+//	    return func(streamCtx context.Context, lastEventID string, emit func(core.SSEEvent[Notification]) error) error {
+//	        for n := range hub.Subscribe(streamCtx, lastEventID) {
+//	            if err := emit(core.SSEEvent[Notification]{ID: n.ID, Event: "notification", Data: n}); err != nil {
+//	                return err
+//	            }
+//	        }
+//	        return nil
+//	    }, nil
+//	}
+//	handler := MakeHandler(reg, RouteInfo{Method: "GET", Path: "/api/v1/notifications"}, Notifications, ResponseSSE)
+func ResponseSSE[ParamTypeT any, BodyTypeT any, EventT any](next handler.Handler[ParamTypeT, BodyTypeT, core.SSEStream[EventT]]) handler.Handler[ParamTypeT, BodyTypeT, core.SSEStream[EventT]] {
+	return ResponseSSEWith[ParamTypeT, BodyTypeT, EventT](core.DefaultSSEOptions())(next)
+}
+
+// ResponseSSEWith is like ResponseSSE but with custom options (e.g. heartbeat interval).
+//
+// Example:
+//
+//	handler := MakeHandler(reg, routeInfo, Notifications,
+//	    ResponseSSEWith[struct{}, struct{}, Notification](core.SSEOptions{HeartbeatInterval: 5 * time.Second}))
+func ResponseSSEWith[ParamTypeT any, BodyTypeT any, EventT any](opts core.SSEOptions) func(next handler.Handler[ParamTypeT, BodyTypeT, core.SSEStream[EventT]]) handler.Handler[ParamTypeT, BodyTypeT, core.SSEStream[EventT]] {
+	return func(next handler.Handler[ParamTypeT, BodyTypeT, core.SSEStream[EventT]]) handler.Handler[ParamTypeT, BodyTypeT, core.SSEStream[EventT]] {
+		return func(ctx handler.HandlerContext[ParamTypeT, BodyTypeT], w http.ResponseWriter, r *http.Request) (core.SSEStream[EventT], error) {
+			// Execute the handler
+			stream, err := next(ctx, w, r)
+			if err != nil {
+				// Don't handle errors here - let the adapter handle them
+				return stream, err
+			}
+			if stream == nil {
+				return stream, core.NewAPIError(http.StatusInternalServerError, "Internal Server Error", "handler returned no event stream")
+			}
+
+			// Serve events until the stream ends or the client disconnects
+			if err := core.ServeSSE(w, r, stream, opts); err != nil {
+				var streamErr *core.StreamError
+				if errors.As(err, &streamErr) {
+					ctx.Logger.Error("Event stream aborted", "error", streamErr.Err.Error(), "events", streamErr.Items, "path", r.URL.Path)
+					return stream, nil
+				}
+				return stream, err
+			}
+
+			return stream, nil
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package typed
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+type sseTick struct {
+	N int `json:"n"`
+}
+
+// TestResponseSSE verifies event formatting, resume, heartbeats and in-band errors
+func TestResponseSSE(t *testing.T) {
+	serve := func(opts core.SSEOptions, lastEventID string, stream core.SSEStream[sseTick]) *httptest.ResponseRecorder {
+		wrappedHandler := ResponseSSEWith[struct{}, struct{}, sseTick](opts)(
+			func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (core.SSEStream[sseTick], error) {
+				return stream, nil
+			},
+		)
+
+		req := httptest.NewRequest("GET", "/events", nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		rec := httptest.NewRecorder()
+		handlerCtx := handler.HandlerContext[struct{}, struct{}]{
+			Context: req.Context(),
+			Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+		if _, err := wrappedHandler(handlerCtx, rec, req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return rec
+	}
+
+	t.Run("writes events and resumes from Last-Event-ID", func(t *testing.T) {
+		var gotLastEventID string
+		rec := serve(core.SSEOptions{}, "41", func(ctx context.Context, lastEventID string, emit func(core.SSEEvent[sseTick]) error) error {
+			gotLastEventID = lastEventID
+			if err := emit(core.SSEEvent[sseTick]{ID: "42", Event: "tick", Data: sseTick{N: 1}}); err != nil {
+				return err
+			}
+			return emit(core.SSEEvent[sseTick]{ID: "43", Data: sseTick{N: 2}})
+		})
+
+		if gotLastEventID != "41" {
+			t.Errorf("Expected Last-Event-ID 41, got %q", gotLastEventID)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != core.EventStreamContentType {
+			t.Errorf("Expected %s, got %s", core.EventStreamContentType, ct)
+		}
+		want := "id: 42\nevent: tick\ndata: {\"n\":1}\n\nid: 43\ndata: {\"n\":2}\n\n"
+		if got := rec.Body.String(); got != want {
+			t.Errorf("Unexpected body:\n%q\nwant:\n%q", got, want)
+		}
+		if !rec.Flushed {
+			t.Error("Expected events to be flushed")
+		}
+	})
+
+	t.Run("sends heartbeats while idle", func(t *testing.T) {
+		rec := serve(core.SSEOptions{HeartbeatInterval: 5 * time.Millisecond}, "", func(ctx context.Context, lastEventID string, emit func(core.SSEEvent[sseTick]) error) error {
+			time.Sleep(30 * time.Millisecond)
+			return nil
+		})
+
+		if !strings.Contains(rec.Body.String(), ": heartbeat\n\n") {
+			t.Errorf("Expected heartbeat comment, got %q", rec.Body.String())
+		}
+	})
+
+	t.Run("reports stream errors as an error event", func(t *testing.T) {
+		rec := serve(core.SSEOptions{}, "", func(ctx context.Context, lastEventID string, emit func(core.SSEEvent[sseTick]) error) error {
+			return errors.New("broker unavailable")
+		})
+
+		body := rec.Body.String()
+		if !strings.Contains(body, "event: error\n") {
+			t.Errorf("Expected error event, got %q", body)
+		}
+		if strings.Contains(body, "broker unavailable") {
+			t.Errorf("Internal error leaked into stream: %q", body)
+		}
+	})
+
+	t.Run("stops when the client disconnects", func(t *testing.T) {
+		events := make(chan core.SSEEvent[sseTick])
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest("GET", "/events", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- core.ServeSSE(rec, req, core.SSEFromChannel(events), core.SSEOptions{})
+		}()
+		cancel()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("Expected nil error on disconnect, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("ServeSSE did not return after client disconnect")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package typed
+
+import (
+	"errors"
+	"iter"
+	"net/http"
+
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+// ResponseStream writes an iterator response incrementally instead of buffering it.
+//
+// The handler returns an iter.Seq2[ItemT, error] (for example from db.QueryIter) and this
+// middleware writes {"data": [...], "count": N} or NDJSON (Accept: application/x-ndjson),
+// flushing periodically using core.DefaultStreamOptions. Status codes follow ResponseJSON.
+//
+// Errors yielded before the first item produce a normal error response. Errors yielded
+// mid-stream terminate the body with an error object and the X-Stream-Error trailer
+// (see core.Stream); they are logged here and not returned, since the response is committed.
+//
+// Dependencies: core.Stream
+// Context modifications: None
+// Use: Apply via MakeHandler(exportHandler, ParseParams, ResponseStream)
+//
+// Example:
+//
+//	func ExportUsers(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (iter.Seq2[User, error], error) {
+//	    return db.QueryIter[User](ctx.Context, ctx.DB, "SELECT id, email FROM users"), nil
+//	}
+//	handler := MakeHandler(reg, RouteInfo{Method: "GET", Path: "/api/v1/users/export"}, ExportUsers, ResponseStream)
+func ResponseStream[ParamTypeT any, BodyTypeT any, ItemT any](next handler.Handler[ParamTypeT, BodyTypeT, iter.Seq2[ItemT, error]]) handler.Handler[ParamTypeT, BodyTypeT, iter.Seq2[ItemT, error]] {
+	return ResponseStreamWith[ParamTypeT, BodyTypeT, ItemT](core.DefaultStreamOptions())(next)
+}
+
+// ResponseStreamWith is like ResponseStream but with custom flush settings.
+//
+// Example:
+//
+//	handler := MakeHandler(reg, routeInfo, ExportUsers,
+//	    ResponseStreamWith[struct{}, struct{}, User](core.StreamOptions{FlushEvery: 500}))
+func ResponseStreamWith[ParamTypeT any, BodyTypeT any, ItemT any](opts core.StreamOptions) func(next handler.Handler[ParamTypeT, BodyTypeT, iter.Seq2[ItemT, error]]) handler.Handler[ParamTypeT, BodyTypeT, iter.Seq2[ItemT, error]] {
+	return func(next handler.Handler[ParamTypeT, BodyTypeT, iter.Seq2[ItemT, error]]) handler.Handler[ParamTypeT, BodyTypeT, iter.Seq2[ItemT, error]] {
+		return func(ctx handler.HandlerContext[ParamTypeT, BodyTypeT], w http.ResponseWriter, r *http.Request) (iter.Seq2[ItemT, error], error) {
+			// Execute the handler
+			seq, err := next(ctx, w, r)
+			if err != nil {
+				// Don't handle errors here - let the adapter handle them
+				return seq, err
+			}
+
+			// Determine appropriate status code based on HTTP method
+			var statusCode int
+			switch r.Method {
+			case "POST":
+				statusCode = 201 // Created
+			default:
+				statusCode = 200 // OK
+			}
+
+			// Stream the items; mid-stream failures are already reported in-band
+			if err := core.Stream(w, r, statusCode, seq, opts); err != nil {
+				var streamErr *core.StreamError
+				if errors.As(err, &streamErr) {
+					ctx.Logger.Error("Stream aborted", "error", streamErr.Err.Error(), "items", streamErr.Items, "path", r.URL.Path)
+					return seq, nil
+				}
+				return seq, err
+			}
+
+			return seq, nil
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package typed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+// seqOf yields items and then, if failAt >= 0, an error at that position
+func seqOf(items []int, failAt int, failErr error) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for i, item := range items {
+			if i == failAt {
+				yield(0, failErr)
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// TestResponseStream verifies incremental JSON and NDJSON list streaming
+func TestResponseStream(t *testing.T) {
+	serve := func(accept string, seq iter.Seq2[int, error]) (*httptest.ResponseRecorder, error) {
+		reg := handler.NewRegistry()
+		wrappedHandler := handler.MakeHandler(reg,
+			handler.RouteInfo{Method: "GET", Path: "/export"},
+			func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (iter.Seq2[int, error], error) {
+				return seq, nil
+			},
+			ResponseStream,
+		)
+
+		req := httptest.NewRequest("GET", "/export", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		handlerCtx := handler.HandlerContext[struct{}, struct{}]{
+			Context: req.Context(),
+			Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+		_, err := wrappedHandler(handlerCtx, rec, req)
+		return rec, err
+	}
+
+	t.Run("writes JSON list envelope", func(t *testing.T) {
+		rec, err := serve("", seqOf([]int{1, 2, 3}, -1, nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var body struct {
+			Data  []int `json:"data"`
+			Count int   `json:"count"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", rec.Body.String(), err)
+		}
+		if body.Count != 3 || len(body.Data) != 3 || body.Data[2] != 3 {
+			t.Errorf("Unexpected body: %+v", body)
+		}
+	})
+
+	t.Run("writes valid JSON for empty sequence", func(t *testing.T) {
+		rec, err := serve("", seqOf(nil, -1, nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := strings.TrimSpace(rec.Body.String()); got != `{"data":[],"count":0}` {
+			t.Errorf("Unexpected body: %s", got)
+		}
+	})
+
+	t.Run("writes NDJSON when requested", func(t *testing.T) {
+		rec, err := serve(core.NDJSONContentType, seqOf([]int{1, 2}, -1, nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != core.NDJSONContentType {
+			t.Errorf("Expected %s, got %s", core.NDJSONContentType, ct)
+		}
+		if got := rec.Body.String(); got != "1\n2\n" {
+			t.Errorf("Unexpected body: %q", got)
+		}
+	})
+
+	t.Run("error before first item is returned", func(t *testing.T) {
+		rec, err := serve("", seqOf([]int{1}, 0, core.ErrNotFound))
+		if !errors.Is(err, core.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("Expected nothing written, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("mid-stream error terminates with error object", func(t *testing.T) {
+		rec, err := serve("", seqOf([]int{1, 2, 3}, 2, errors.New("connection reset")))
+		if err != nil {
+			t.Fatalf("Expected error to be handled in-band, got %v", err)
+		}
+		var body struct {
+			Data  []int         `json:"data"`
+			Count int           `json:"count"`
+			Error core.APIError `json:"error"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", rec.Body.String(), err)
+		}
+		if body.Count != 2 || body.Error.Code != http.StatusInternalServerError {
+			t.Errorf("Unexpected body: %+v", body)
+		}
+		if strings.Contains(rec.Body.String(), "connection reset") {
+			t.Errorf("Internal error leaked into response: %s", rec.Body.String())
+		}
+		if rec.Result().Trailer.Get(core.StreamErrorTrailer) == "" {
+			t.Errorf("Expected %s trailer to be set", core.StreamErrorTrailer)
+		}
+	})
+}
+
+// serveStream runs ResponseStream for a handler returning seq
+func serveStream[T any](req *http.Request, seq iter.Seq2[T, error]) (*httptest.ResponseRecorder, error) {
+	reg := handler.NewRegistry()
+	wrappedHandler := handler.MakeHandler(reg,
+		handler.RouteInfo{Method: "GET", Path: "/export"},
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (iter.Seq2[T, error], error) {
+			return seq, nil
+		},
+		ResponseStream,
+	)
+
+	rec := httptest.NewRecorder()
+	handlerCtx := handler.HandlerContext[struct{}, struct{}]{
+		Context: req.Context(),
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	_, err := wrappedHandler(handlerCtx, rec, req)
+	return rec, err
+}
+
+// TestResponseStreamFailures verifies failures after the response started are reported in-band
+func TestResponseStreamFailures(t *testing.T) {
+	type streamBody struct {
+		Data  []json.RawMessage `json:"data"`
+		Count int               `json:"count"`
+		Error *core.APIError    `json:"error"`
+	}
+
+	t.Run("deadline mid-stream", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		req := httptest.NewRequest("GET", "/export", nil).WithContext(ctx)
+
+		seq := func(yield func(int, error) bool) {
+			if !yield(1, nil) {
+				return
+			}
+			cancel() // the deadline passes while the stream is being written
+			yield(2, nil)
+		}
+		rec, err := serveStream(req, iter.Seq2[int, error](seq))
+		if err != nil {
+			t.Fatalf("Expected the error to be handled in-band, got %v", err)
+		}
+
+		var body streamBody
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected a single valid JSON document, got %q: %v", rec.Body.String(), err)
+		}
+		if body.Count != 1 || body.Error == nil {
+			t.Errorf("Expected one item and an error object, got %+v", body)
+		}
+		if rec.Result().Trailer.Get(core.StreamErrorTrailer) == "" {
+			t.Errorf("Expected %s trailer to be set", core.StreamErrorTrailer)
+		}
+	})
+
+	t.Run("marshal error mid-stream", func(t *testing.T) {
+		seq := func(yield func(float64, error) bool) {
+			if !yield(1.5, nil) {
+				return
+			}
+			yield(math.NaN(), nil) // not representable in JSON
+		}
+		rec, err := serveStream(httptest.NewRequest("GET", "/export", nil), iter.Seq2[float64, error](seq))
+		if err != nil {
+			t.Fatalf("Expected the error to be handled in-band, got %v", err)
+		}
+
+		var body streamBody
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected a single valid JSON document, got %q: %v", rec.Body.String(), err)
+		}
+		if body.Count != 1 || body.Error == nil || body.Error.Code != http.StatusInternalServerError {
+			t.Errorf("Expected one item and a 500 error object, got %+v", body)
+		}
+		if rec.Result().Trailer.Get(core.StreamErrorTrailer) == "" {
+			t.Errorf("Expected %s trailer to be set", core.StreamErrorTrailer)
+		}
+	})
+
+	t.Run("redacts server errors mid-stream", func(t *testing.T) {
+		dbErr := core.Wrap(errors.New(`pq: relation "accounts" does not exist`), http.StatusInternalServerError,
+			"Database error", `relation "accounts" does not exist`)
+		for name, failure := range map[string]error{"api error": dbErr, "unexpected error": errors.New("dial tcp 10.0.0.5:5432: refused")} {
+			req := httptest.NewRequest("GET", "/export", nil)
+			req.Header.Set("X-Request-ID", "req-stream")
+			rec, err := serveStream(req, seqOf([]int{1, 2}, 1, failure))
+			if err != nil {
+				t.Fatalf("%s: expected the error to be handled in-band, got %v", name, err)
+			}
+
+			var body streamBody
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("%s: expected a single valid JSON document, got %q: %v", name, rec.Body.String(), err)
+			}
+			if body.Error == nil || body.Error.Detail != "" || body.Error.CorrelationID != "req-stream" {
+				t.Errorf("%s: expected a redacted error with a correlation ID, got %+v", name, body.Error)
+			}
+			if strings.Contains(rec.Body.String(), "accounts") || strings.Contains(rec.Body.String(), "10.0.0.5") {
+				t.Errorf("%s: internal details leaked: %s", name, rec.Body.String())
+			}
+		}
+	})
+
+	t.Run("marshal error before the first item", func(t *testing.T) {
+		seq := func(yield func(float64, error) bool) {
+			yield(math.Inf(1), nil)
+		}
+		rec, err := serveStream(httptest.NewRequest("GET", "/export", nil), iter.Seq2[float64, error](seq))
+		var streamErr *core.StreamError
+		if err == nil || errors.As(err, &streamErr) {
+			t.Errorf("Expected a plain error for a normal error response, got %v", err)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("Expected nothing written, got %s", rec.Body.String())
+		}
+	})
+}
+
+// TestResponseStreamProvidedValue verifies provided values stay open while a streamed body reads through them
+func TestResponseStreamProvidedValue(t *testing.T) {
+	type rowSource struct {
+		rows   []int
+		closed bool
+	}
+
+	var disposed []error
+	reg := handler.NewRegistry()
+	handler.Provide(reg, func(ctx handler.HookContext) (*rowSource, handler.Dispose, error) {
+		source := &rowSource{rows: []int{1, 2, 3}}
+		return source, func(handlerErr error) error {
+			source.closed = true
+			disposed = append(disposed, handlerErr)
+			return nil
+		}, nil
+	})
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "GET", Path: "/rows"},
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (iter.Seq2[int, error], error) {
+			source, err := handler.Resolve[*rowSource](ctx)
+			if err != nil {
+				return nil, err
+			}
+			return func(yield func(int, error) bool) {
+				for _, row := range source.rows {
+					if source.closed {
+						yield(0, errors.New("row source closed"))
+						return
+					}
+					if !yield(row, nil) {
+						return
+					}
+				}
+			}, nil
+		},
+		ResponseStream,
+	)
+
+	r := chi.NewRouter()
+	reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/rows", nil))
+
+	var body struct {
+		Data  []int `json:"data"`
+		Count int   `json:"count"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Count != 3 {
+		t.Fatalf("Expected all rows to be streamed, got %q (%v)", rec.Body.String(), err)
+	}
+	if len(disposed) != 1 || disposed[0] != nil {
+		t.Errorf("Expected one successful disposal after the stream, got %v", disposed)
+	}
+}
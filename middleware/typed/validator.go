@@ -16,18 +16,31 @@ var validate = validator.New()
 func init() {
 	// Register a function to use JSON tag names in validation errors
 	// This ensures field names in error messages match the JSON API contract
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		jsonTag := fld.Tag.Get("json")
-		if jsonTag != "" && jsonTag != "-" {
-			// Extract field name from json tag (before comma)
-			name := strings.Split(jsonTag, ",")[0]
-			if name != "" {
-				return name
-			}
+	validate.RegisterTagNameFunc(fieldName)
+}
+
+// fieldName returns the name of a struct field in the API contract: its json tag, or for
+// parameter structs its param/query tag
+func fieldName(fld reflect.StructField) string {
+	if name := jsonTagName(fld); name != "" {
+		return name
+	}
+	for _, tag := range []string{"param", "query"} {
+		if name := fld.Tag.Get(tag); name != "" {
+			return name
 		}
-		// Fallback to snake_case conversion of field name
-		return toSnakeCase(fld.Name)
-	})
+	}
+	// Fallback to snake_case conversion of field name
+	return toSnakeCase(fld.Name)
+}
+
+// jsonTagName extracts the field name from a json tag (before the comma), or "" if there is none
+func jsonTagName(fld reflect.StructField) string {
+	jsonTag := fld.Tag.Get("json")
+	if jsonTag == "" || jsonTag == "-" {
+		return ""
+	}
+	return strings.Split(jsonTag, ",")[0]
 }
 
 // toSnakeCase converts PascalCase/camelCase to snake_case
@@ -173,25 +173,21 @@ type ValidatorSetup struct {
 
 // Example: Custom Error Messages
 //
-// When using custom validators, update the error message generator in your
-// middleware/typed/request.go or create a custom one:
-//
-//	func generateFieldErrorMessage(fieldError validator.FieldError) string {
-//	    fieldName := fieldError.Field()
-//	    tag := fieldError.Tag()
-//	    param := fieldError.Param()
-//
-//	    switch tag {
-//	    case "unique_email":
-//	        return "A user with this email already exists"
-//	    case "user_exists":
-//	        return "User does not exist"
-//	    case "valid_status":
-//	        return fmt.Sprintf("Status must be one of: %s", param)
-//	    default:
-//	        return fmt.Sprintf("%s validation failed", fieldName)
-//	    }
-//	}
+// Validation messages come from a core.MessageCatalog keyed by "validation.<tag>",
+// so custom validators only need a template per tag (and per language).
+// The locale is negotiated from the request's Accept-Language header:
+//
+//	core.RegisterMessages("en", map[string]string{
+//	    "validation.unique_email": "A user with this email already exists",
+//	    "validation.user_exists":  "User does not exist",
+//	    "validation.valid_status": "{field} must be one of: {param}",
+//	})
+//	core.RegisterMessages("de", map[string]string{
+//	    "validation.required":     "{field} ist erforderlich",
+//	    "validation.unique_email": "Ein Benutzer mit dieser E-Mail-Adresse existiert bereits",
+//	})
+//
+// Placeholders: {field} (struct field name), {param} (tag parameter), {tag} (tag name).
 
 // For more information on custom validators, see:
 // https://pkg.go.dev/github.com/go-playground/validator/v10#Validate.RegisterValidation
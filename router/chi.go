@@ -7,13 +7,14 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/platform-smith-labs/japi-core/v3/core"
+	httpMiddleware "github.com/platform-smith-labs/japi-core/v3/middleware/http"
 )
 
-// RouterOption is a functional option that configures the Chi router's CORS settings.
+// RouterOption is a functional option that configures the Chi router's CORS and error settings.
 // Use the With* functions to construct options; pass them to NewChiRouterWithOptions.
 type RouterOption func(*routerConfig)
 
-// routerConfig holds CORS configuration used during router construction.
+// routerConfig holds CORS and error configuration used during router construction.
 // It is unexported — callers interact only via RouterOption functions.
 type routerConfig struct {
 	allowedOrigins   []string
@@ -22,6 +23,10 @@ type routerConfig struct {
 	exposedHeaders   []string
 	allowCredentials bool
 	maxAge           int
+	errorFormat      core.ErrorFormat
+	errorMode        core.ErrorMode
+	messageCatalog   *core.MessageCatalog
+	compression      *httpMiddleware.CompressionConfig
 }
 
 // defaultRouterConfig returns the secure baseline CORS configuration.
@@ -35,6 +40,8 @@ func defaultRouterConfig() routerConfig {
 		exposedHeaders:   []string{"Link"},
 		allowCredentials: false,
 		maxAge:           300,
+		errorFormat:      core.ErrorFormatEnvelope,
+		errorMode:        core.ErrorModeProduction,
 	}
 }
 
@@ -75,6 +82,33 @@ func WithMaxAge(seconds int) RouterOption {
 	return func(cfg *routerConfig) { cfg.maxAge = seconds }
 }
 
+// WithErrorFormat selects the wire format for error responses written through core.WriteAPIError,
+// including AdaptErrorHandler and typed handlers mounted on this router.
+// Defaults to core.ErrorFormatEnvelope; use core.ErrorFormatProblem for RFC 9457 problem+json.
+func WithErrorFormat(format core.ErrorFormat) RouterOption {
+	return func(cfg *routerConfig) { cfg.errorFormat = format }
+}
+
+// WithErrorMode controls whether unexpected errors expose their text to clients.
+// Defaults to core.ErrorModeProduction, which sends a generic message with a correlation ID
+// and logs the full error chain; use core.ErrorModeDevelopment locally to see error details.
+func WithErrorMode(mode core.ErrorMode) RouterOption {
+	return func(cfg *routerConfig) { cfg.errorMode = mode }
+}
+
+// WithMessageCatalog selects the catalog used to localise validation and coded error messages
+// for requests served by this router, in the language negotiated from Accept-Language.
+// Defaults to core.DefaultMessages.
+func WithMessageCatalog(catalog *core.MessageCatalog) RouterOption {
+	return func(cfg *routerConfig) { cfg.messageCatalog = catalog }
+}
+
+// WithCompression enables negotiated gzip/brotli/zstd response compression for every route.
+// Disabled by default; pass httpMiddleware.DefaultCompressionConfig() for sensible settings.
+func WithCompression(config httpMiddleware.CompressionConfig) RouterOption {
+	return func(cfg *routerConfig) { cfg.compression = &config }
+}
+
 // newChiRouter is the single internal constructor all public constructors delegate to.
 // It applies defaults then each option in order, constructs the chi router, and attaches
 // the standard middleware stack and CORS handler exactly once.
@@ -87,7 +121,12 @@ func newChiRouter(opts ...RouterOption) chi.Router {
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Recoverer)
+	r.Use(core.WithErrorFormat(cfg.errorFormat))
+	r.Use(core.WithErrorMode(cfg.errorMode))
+	if cfg.messageCatalog != nil {
+		r.Use(core.WithMessageCatalog(cfg.messageCatalog))
+	}
+	r.Use(core.Recoverer)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   cfg.allowedOrigins,
 		AllowedMethods:   cfg.allowedMethods,
@@ -96,6 +135,9 @@ func newChiRouter(opts ...RouterOption) chi.Router {
 		AllowCredentials: cfg.allowCredentials,
 		MaxAge:           cfg.maxAge,
 	}))
+	if cfg.compression != nil {
+		r.Use(httpMiddleware.WithCompression(*cfg.compression))
+	}
 	return r
 }
 
@@ -142,14 +184,12 @@ func NewChiRouterWithOptions(opts ...RouterOption) chi.Router {
 }
 
 // AdaptErrorHandler adapts a core.HandlerFunc to work with Chi.
+// Errors are written in the ErrorFormat selected for the request (see WithErrorFormat) and
+// redacted according to its ErrorMode (see WithErrorMode).
 func AdaptErrorHandler(handler core.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if err := handler(w, r); err != nil {
-			if apiErr, ok := err.(*core.APIError); ok {
-				core.WriteAPIError(w, r, *apiErr)
-			} else {
-				core.Error(w, r, http.StatusInternalServerError, "Internal Server Error")
-			}
+			core.WriteError(w, r, err)
 		}
 	}
 }
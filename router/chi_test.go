@@ -1,11 +1,15 @@
 package router_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/platform-smith-labs/japi-core/v3/core"
 	"github.com/platform-smith-labs/japi-core/v3/router"
 )
 
@@ -110,3 +114,178 @@ func TestNewChiRouterWithOptions_OriginAndMethods(t *testing.T) {
 		t.Errorf("Access-Control-Allow-Methods = %q; want it to contain PATCH", methods)
 	}
 }
+
+// TestWithErrorFormat_Problem verifies that WithErrorFormat(core.ErrorFormatProblem)
+// makes AdaptErrorHandler write RFC 9457 problem details.
+func TestWithErrorFormat_Problem(t *testing.T) {
+	r := router.NewChiRouterWithOptions(router.WithErrorFormat(core.ErrorFormatProblem))
+	r.Get("/missing", router.AdaptErrorHandler(func(w http.ResponseWriter, r *http.Request) error {
+		return core.NewAPIError(http.StatusNotFound, "Not Found", "user does not exist")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d; want 404", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != core.ProblemContentType {
+		t.Errorf("Content-Type = %q; want %q", ct, core.ProblemContentType)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if body["title"] != "Not Found" || body["detail"] != "user does not exist" || body["status"] != float64(404) {
+		t.Errorf("unexpected problem body: %v", body)
+	}
+	if body["instance"] == nil || body["instance"] == "" {
+		t.Errorf("instance = %v; want the request ID", body["instance"])
+	}
+}
+
+// TestWithErrorFormat_DefaultEnvelope verifies the default error envelope is unchanged.
+func TestWithErrorFormat_DefaultEnvelope(t *testing.T) {
+	r := router.NewChiRouter()
+	r.Get("/missing", router.AdaptErrorHandler(func(w http.ResponseWriter, r *http.Request) error {
+		return core.NewAPIError(http.StatusNotFound, "Not Found")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q; want application/json", ct)
+	}
+	var body map[string]map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if body["error"]["code"] != float64(404) {
+		t.Errorf("unexpected envelope body: %v", body)
+	}
+}
+
+// TestWithErrorMode verifies unexpected errors are redacted in production and
+// exposed in development, and that APIErrors can opt in to exposing their detail.
+func TestWithErrorMode(t *testing.T) {
+	leaky := func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("load user: %w", errors.New(`pq: relation "users" does not exist`))
+	}
+	exposed := func(w http.ResponseWriter, r *http.Request) error {
+		return core.NewAPIError(http.StatusBadGateway, "Upstream failed").WithExposedDetail("payment provider unavailable")
+	}
+	hidden := func(w http.ResponseWriter, r *http.Request) error {
+		return core.NewAPIError(http.StatusInternalServerError, "Export failed", "open /var/data/export.csv: permission denied")
+	}
+
+	serve := func(opts []router.RouterOption, h core.HandlerFunc) map[string]any {
+		r := router.NewChiRouterWithOptions(opts...)
+		r.Get("/", router.AdaptErrorHandler(h))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		var body map[string]map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON body: %v", err)
+		}
+		return body["error"]
+	}
+
+	t.Run("production redacts unexpected errors", func(t *testing.T) {
+		body := serve(nil, leaky)
+		if body["message"] != "Internal Server Error" || body["detail"] != nil {
+			t.Errorf("unexpected body: %v", body)
+		}
+		if id, _ := body["correlation_id"].(string); id == "" {
+			t.Errorf("correlation_id missing: %v", body)
+		}
+	})
+
+	t.Run("production redacts 5xx APIError detail", func(t *testing.T) {
+		body := serve(nil, hidden)
+		if body["message"] != "Export failed" || body["detail"] != nil {
+			t.Errorf("unexpected body: %v", body)
+		}
+	})
+
+	t.Run("exposed detail survives production", func(t *testing.T) {
+		body := serve(nil, exposed)
+		if body["detail"] != "payment provider unavailable" {
+			t.Errorf("detail = %v; want exposed detail", body["detail"])
+		}
+	})
+
+	t.Run("development keeps details", func(t *testing.T) {
+		body := serve([]router.RouterOption{router.WithErrorMode(core.ErrorModeDevelopment)}, leaky)
+		if detail, _ := body["detail"].(string); !strings.Contains(detail, `relation "users"`) {
+			t.Errorf("detail = %v; want the underlying error", body["detail"])
+		}
+	})
+}
+
+// TestRecoverer verifies panics produce the JSON error envelope instead of chi's plain-text 500.
+func TestRecoverer(t *testing.T) {
+	r := router.NewChiRouter()
+	r.Get("/boom", func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map write")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d; want 500", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q; want application/json", ct)
+	}
+	var body map[string]map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if body["error"]["message"] != "Internal Server Error" || body["error"]["detail"] != nil {
+		t.Errorf("unexpected envelope body: %v", body)
+	}
+	if id, _ := body["error"]["correlation_id"].(string); id == "" {
+		t.Errorf("correlation_id missing: %v", body)
+	}
+}
+
+// TestAdaptErrorHandler_WrappedAPIError verifies wrapped APIErrors keep their status.
+func TestAdaptErrorHandler_WrappedAPIError(t *testing.T) {
+	errNoRows := errors.New("sql: no rows in result set")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"wrapped with fmt.Errorf", fmt.Errorf("load user: %w", core.NewAPIError(http.StatusNotFound, "User not found")), http.StatusNotFound},
+		{"core.Wrap", core.Wrap(errNoRows, http.StatusNotFound, "User not found"), http.StatusNotFound},
+		{"shared error with cause", fmt.Errorf("lookup: %w", core.ErrForbidden.WithCause(errNoRows)), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := router.NewChiRouter()
+			r.Get("/users/1", router.AdaptErrorHandler(func(w http.ResponseWriter, r *http.Request) error {
+				return tt.err
+			}))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d; want %d", w.Code, tt.want)
+			}
+			if strings.Contains(w.Body.String(), "no rows") {
+				t.Errorf("cause leaked to client: %s", w.Body.String())
+			}
+		})
+	}
+}
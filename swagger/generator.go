@@ -3,13 +3,16 @@ package swagger
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-openapi/spec"
 	"github.com/swaggo/swag"
+	"github.com/platform-smith-labs/japi-core/v3/core"
 	"github.com/platform-smith-labs/japi-core/v3/handler"
 )
 
@@ -50,8 +53,14 @@ const docTemplate = `{
     }
 }`
 
-// GenerateSpec creates an OpenAPI spec from collected routes using reflection
+// GenerateSpec creates an OpenAPI spec from collected routes using reflection.
+// Error codes declared in RouteInfo.ErrorCodes are resolved against core.DefaultErrorCatalog.
 func GenerateSpec(registry *handler.Registry) *spec.Swagger {
+	return GenerateSpecWithCatalog(registry, core.DefaultErrorCatalog)
+}
+
+// GenerateSpecWithCatalog creates an OpenAPI spec, resolving route error codes against the given catalog
+func GenerateSpecWithCatalog(registry *handler.Registry, catalog *core.ErrorCatalog) *spec.Swagger {
 	swagger := &spec.Swagger{
 		SwaggerProps: spec.SwaggerProps{
 			Swagger: "2.0",
@@ -80,8 +89,8 @@ func GenerateSpec(registry *handler.Registry) *spec.Swagger {
 		},
 	}
 
-	// Process collected routes from handler package
-	routes := registry.GetRoutes()
+	// Process the routes the registry serves; skipped duplicates are never reached
+	routes := registry.ServedRoutes()
 
 	// Group routes by path to handle multiple HTTP methods for the same path
 	routesByPath := make(map[string][]handler.PendingRoute)
@@ -91,28 +100,46 @@ func GenerateSpec(registry *handler.Registry) *spec.Swagger {
 
 	// Generate PathItems for each unique path, combining all HTTP methods
 	for path, pathRoutes := range routesByPath {
-		pathItem := generatePathItemFromRoutes(pathRoutes, swagger)
+		pathItem := generatePathItemFromRoutes(pathRoutes, swagger, catalog)
 		if pathItem != nil {
 			swagger.Paths.Paths[path] = *pathItem
 		}
 	}
 
+	// Publish the full error catalog alongside the operations
+	if catalog != nil {
+		if defs := catalog.Definitions(); len(defs) > 0 {
+			swagger.AddExtension("x-error-catalog", defs)
+		}
+	}
+
 	return swagger
 }
 
 // generatePathItemFromRoutes creates a PathItem from multiple routes with the same path
-func generatePathItemFromRoutes(routes []handler.PendingRoute, swagger *spec.Swagger) *spec.PathItem {
+func generatePathItemFromRoutes(routes []handler.PendingRoute, swagger *spec.Swagger, catalog *core.ErrorCatalog) *spec.PathItem {
 	pathItem := &spec.PathItem{}
 
-	// Process each route and add its operation to the appropriate HTTP method
+	// Versions of one method are documented together on a single operation
+	var methods []string
+	routesByMethod := make(map[string][]handler.PendingRoute)
 	for _, route := range routes {
-		operation := generateOperation(route, swagger)
+		method := strings.ToUpper(route.Method)
+		if _, ok := routesByMethod[method]; !ok {
+			methods = append(methods, method)
+		}
+		routesByMethod[method] = append(routesByMethod[method], route)
+	}
+
+	// Process each route and add its operation to the appropriate HTTP method
+	for _, method := range methods {
+		operation := generateVersionedOperation(routesByMethod[method], swagger, catalog)
 		if operation == nil {
 			continue
 		}
 
 		// Add operation to appropriate method
-		switch strings.ToUpper(route.Method) {
+		switch method {
 		case "GET":
 			pathItem.Get = operation
 		case "POST":
@@ -139,20 +166,125 @@ func generatePathItemFromRoutes(routes []handler.PendingRoute, swagger *spec.Swa
 	return pathItem
 }
 
+// generateVersionedOperation documents the first route of a method, listing every version's
+// parameters, success responses and lifecycle under x-api-versions and adding the API-Version header
+// parameter. The operation's own parameters combine those of every version.
+// A single route, or routes without versions, produce a plain operation of the first route,
+// the one RegisterWithRouter serves.
+func generateVersionedOperation(routes []handler.PendingRoute, swagger *spec.Swagger, catalog *core.ErrorCatalog) *spec.Operation {
+	versioned := len(routes) > 1
+	for _, route := range routes {
+		if route.RouteInfo.Version == "" {
+			versioned = false
+		}
+	}
+	if !versioned {
+		return generateOperation(routes[0], swagger, catalog)
+	}
+
+	operation := generateOperation(routes[0], swagger, catalog)
+	versions := make(map[string]any, len(routes))
+	names := make([]any, len(routes))
+	parameters := make([][]spec.Parameter, len(routes))
+	allDeprecated := true
+	for i, route := range routes {
+		versionOperation := generateOperation(route, swagger, catalog)
+		success := make(map[string]spec.Response)
+		for status, response := range versionOperation.Responses.StatusCodeResponses {
+			if status < 300 || status == http.StatusNotModified {
+				success[strconv.Itoa(status)] = response
+			}
+		}
+		version := map[string]any{
+			"summary":    versionOperation.Summary,
+			"deprecated": versionOperation.Deprecated,
+			"parameters": versionOperation.Parameters,
+			"responses":  success,
+		}
+		for _, extension := range []string{"x-sunset", "x-successor"} {
+			if value, ok := versionOperation.Extensions[extension]; ok {
+				version[extension] = value
+			}
+		}
+		versions[route.RouteInfo.Version] = version
+		names[i] = route.RouteInfo.Version
+		parameters[i] = versionOperation.Parameters
+		allDeprecated = allDeprecated && versionOperation.Deprecated
+	}
+
+	// The operation stands for every version; lifecycle details are listed per version
+	operation.Deprecated = allDeprecated
+	for _, extension := range []string{"x-api-version", "x-sunset", "x-successor"} {
+		delete(operation.Extensions, extension)
+	}
+	operation.AddExtension("x-api-versions", versions)
+	operation.Parameters = mergeVersionParameters(parameters)
+	operation.Parameters = append(operation.Parameters, spec.Parameter{
+		ParamProps: spec.ParamProps{
+			Name:        handler.DefaultVersionHeader,
+			In:          "header",
+			Description: "API version; also selectable with an application/vnd.<vendor>.<version>+json Accept header",
+		},
+		SimpleSchema: spec.SimpleSchema{Type: "string"},
+		CommonValidations: spec.CommonValidations{Enum: names},
+	})
+	return operation
+}
+
+// mergeVersionParameters combines the parameters of every version of an operation, in order of
+// first appearance. A parameter is required only when every version requires it, and a request body
+// whose schema differs between versions is documented as a oneOf of the version schemas.
+func mergeVersionParameters(versions [][]spec.Parameter) []spec.Parameter {
+	var merged []spec.Parameter
+	index := make(map[string]int)
+	counts := make(map[string]int)
+	var bodies []spec.Schema
+	seenBodies := make(map[string]bool)
+	for _, parameters := range versions {
+		for _, parameter := range parameters {
+			key := parameter.In + ":" + parameter.Name
+			counts[key]++
+			if parameter.In == "body" && parameter.Schema != nil && !seenBodies[parameter.Schema.Ref.String()] {
+				seenBodies[parameter.Schema.Ref.String()] = true
+				bodies = append(bodies, *parameter.Schema)
+			}
+
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(merged)
+				merged = append(merged, parameter)
+				continue
+			}
+			merged[i].Required = merged[i].Required && parameter.Required
+		}
+	}
+
+	for i, parameter := range merged {
+		if counts[parameter.In+":"+parameter.Name] < len(versions) {
+			merged[i].Required = false
+		}
+		if parameter.In == "body" && len(bodies) > 1 {
+			merged[i].Description = "Request body; its schema depends on the API version (see x-api-versions)"
+			merged[i].Schema = &spec.Schema{SchemaProps: spec.SchemaProps{OneOf: bodies}}
+		}
+	}
+	return merged
+}
+
 // generatePathItem creates a PathItem from a single route using reflection (legacy function for backward compatibility)
 func generatePathItem(route handler.PendingRoute, swagger *spec.Swagger) *spec.PathItem {
-	return generatePathItemFromRoutes([]handler.PendingRoute{route}, swagger)
+	return generatePathItemFromRoutes([]handler.PendingRoute{route}, swagger, core.DefaultErrorCatalog)
 }
 
 // generateOperation creates an Operation from a route using reflection
-func generateOperation(route handler.PendingRoute, swagger *spec.Swagger) *spec.Operation {
+func generateOperation(route handler.PendingRoute, swagger *spec.Swagger, catalog *core.ErrorCatalog) *spec.Operation {
 	operation := &spec.Operation{
 		OperationProps: spec.OperationProps{
 			Summary:     generateSummary(route),
 			Description: generateDescription(route),
 			Tags:        generateTags(route),
 			Consumes:    []string{"application/json"},
-			Produces:    []string{"application/json"},
+			Produces:    generateProduces(route),
 			Parameters:  []spec.Parameter{},
 			Responses:   &spec.Responses{ResponsesProps: spec.ResponsesProps{StatusCodeResponses: make(map[int]spec.Response)}},
 		},
@@ -187,7 +319,12 @@ func generateOperation(route handler.PendingRoute, swagger *spec.Swagger) *spec.
 	}
 
 	// Check for authentication requirement based on middleware
-	if requiresAuth(route) {
+	if len(route.RouteInfo.Security) > 0 {
+		// Any one of the declared schemes satisfies the requirement
+		for _, scheme := range route.RouteInfo.Security {
+			operation.Security = append(operation.Security, map[string][]string{scheme: {}})
+		}
+	} else if requiresAuth(route) {
 		operation.Security = []map[string][]string{
 			{"BearerAuth": []string{}},
 		}
@@ -196,9 +333,117 @@ func generateOperation(route handler.PendingRoute, swagger *spec.Swagger) *spec.
 	// Add standard responses
 	addStandardResponses(operation, swagger)
 
+	// Document the catalog error codes this route declares
+	addErrorCodeResponses(operation, route, catalog)
+
+	// Document caching headers and conditional request responses
+	addConditionalResponses(operation, route)
+
+	// Move the success response to the status codes the route declares
+	addDeclaredStatusResponses(operation, route)
+
+	// Document the route's version and deprecation lifecycle
+	addLifecycle(operation, route)
+
 	return operation
 }
 
+// addLifecycle marks deprecated operations and records the route's version, sunset date and
+// successor as x-api-version, x-sunset and x-successor extensions
+func addLifecycle(operation *spec.Operation, route handler.PendingRoute) {
+	info := route.RouteInfo
+	if info.Version != "" {
+		operation.AddExtension("x-api-version", info.Version)
+	}
+	if !info.IsDeprecated() {
+		return
+	}
+
+	operation.Deprecated = true
+	if !info.Sunset.IsZero() {
+		operation.AddExtension("x-sunset", info.Sunset.UTC().Format(time.RFC3339))
+	}
+	if info.Successor != "" {
+		operation.AddExtension("x-successor", info.Successor)
+	}
+}
+
+// addDeclaredStatusResponses documents the success response under each of the route's
+// RouteInfo.StatusCodes instead of 200. 204 and 304 responses are documented without a body.
+func addDeclaredStatusResponses(operation *spec.Operation, route handler.PendingRoute) {
+	if len(route.RouteInfo.StatusCodes) == 0 {
+		return
+	}
+
+	success := operation.Responses.StatusCodeResponses[200]
+	delete(operation.Responses.StatusCodeResponses, 200)
+	for _, status := range route.RouteInfo.StatusCodes {
+		response := success
+		response.Description = http.StatusText(status)
+		if status == http.StatusNoContent || status == http.StatusNotModified {
+			response.Schema = nil
+		}
+		operation.Responses.StatusCodeResponses[status] = response
+	}
+}
+
+// addConditionalResponses documents Cache-Control, ETag, 304 and 412 based on the route's
+// CacheControl policy and conditional middleware. The headers are attached to the operation's
+// success response; they are skipped if it documents none.
+func addConditionalResponses(operation *spec.Operation, route handler.PendingRoute) {
+	successStatus, hasSuccess := lowestSuccessStatus(operation)
+	success := operation.Responses.StatusCodeResponses[successStatus]
+	addHeader := func(name, description string) {
+		if !hasSuccess {
+			return
+		}
+		if success.Headers == nil {
+			success.Headers = make(map[string]spec.Header)
+		}
+		success.Headers[name] = spec.Header{
+			HeaderProps:  spec.HeaderProps{Description: description},
+			SimpleSchema: spec.SimpleSchema{Type: "string"},
+		}
+	}
+
+	if route.RouteInfo.CacheControl != "" {
+		addHeader("Cache-Control", route.RouteInfo.CacheControl)
+	}
+
+	for _, middlewareName := range route.MiddlewareNames {
+		switch middlewareName {
+		case "ResponseETag", "ResponseETagWith":
+			addHeader("ETag", "Entity tag of the returned representation")
+			operation.Responses.StatusCodeResponses[http.StatusNotModified] = spec.Response{
+				ResponseProps: spec.ResponseProps{
+					Description: "Not Modified - the If-None-Match / If-Modified-Since representation is current",
+				},
+			}
+		case "Preconditions":
+			operation.Responses.StatusCodeResponses[http.StatusPreconditionFailed] = spec.Response{
+				ResponseProps: spec.ResponseProps{
+					Description: "Precondition Failed - If-Match / If-Unmodified-Since does not match the current resource",
+				},
+			}
+		}
+	}
+
+	if hasSuccess {
+		operation.Responses.StatusCodeResponses[successStatus] = success
+	}
+}
+
+// lowestSuccessStatus returns the lowest 2xx status the operation documents
+func lowestSuccessStatus(operation *spec.Operation) (int, bool) {
+	lowest := 0
+	for status := range operation.Responses.StatusCodeResponses {
+		if status >= 200 && status < 300 && (lowest == 0 || status < lowest) {
+			lowest = status
+		}
+	}
+	return lowest, lowest != 0
+}
+
 // addParametersFromContext extracts parameters from HandlerContext type
 func addParametersFromContext(operation *spec.Operation, contextType reflect.Type, swagger *spec.Swagger) {
 	for i := 0; i < contextType.NumField(); i++ {
@@ -247,12 +492,27 @@ func addResponseBodyFromHandler(operation *spec.Operation, handlerType reflect.T
 				// First return value should be ResponseBodyT, second is error
 				responseType := funcType.Out(0)
 
+				// handler.Response[T] documents T; its status codes come from RouteInfo.StatusCodes
+				if bodyType, ok := responseBodyType(responseType); ok {
+					responseType = bodyType
+				}
+
 				// Handle struct types
-				if responseType.Kind() == reflect.Struct && responseType != reflect.TypeOf(struct{}{}) {
+				if isPageType(responseType) {
+					// Paginated lists (core.Page[models.User]) also carry a Link header
+					addResponseBodyFromStruct(operation, responseType, swagger)
+					addPageLinkHeader(operation)
+				} else if responseType.Kind() == reflect.Struct && responseType != reflect.TypeOf(struct{}{}) {
 					addResponseBodyFromStruct(operation, responseType, swagger)
 				} else if responseType.Kind() == reflect.Slice || responseType.Kind() == reflect.Array {
 					// Handle slice/array types (e.g., []models.User)
 					addResponseBodyFromSlice(operation, responseType, swagger)
+				} else if itemType, ok := streamItemType(responseType); ok {
+					// Handle streamed iterators (e.g., iter.Seq2[models.User, error])
+					addResponseBodyFromStream(operation, itemType, swagger)
+				} else if eventType, ok := sseEventDataType(responseType); ok {
+					// Handle Server-Sent Events streams (core.SSEStream[models.Notification])
+					addResponseBodyFromSSE(operation, eventType, swagger)
 				}
 			}
 			break
@@ -314,7 +574,7 @@ func addParametersFromStruct(operation *spec.Operation, structType reflect.Type)
 
 // addRequestBodyFromStruct creates request body schema from struct
 func addRequestBodyFromStruct(operation *spec.Operation, structType reflect.Type, swagger *spec.Swagger) {
-	schemaName := structType.Name()
+	schemaName := definitionName(structType)
 
 	// Generate schema definition with nested struct support
 	schema := generateSchemaFromStructWithDefinitions(structType, swagger.Definitions)
@@ -339,7 +599,7 @@ func addRequestBodyFromStruct(operation *spec.Operation, structType reflect.Type
 
 // addResponseBodyFromStruct creates response body schema from struct
 func addResponseBodyFromStruct(operation *spec.Operation, structType reflect.Type, swagger *spec.Swagger) {
-	schemaName := structType.Name()
+	schemaName := definitionName(structType)
 
 	// Generate schema definition with nested struct support
 	schema := generateSchemaFromStructWithDefinitions(structType, swagger.Definitions)
@@ -377,7 +637,7 @@ func addResponseBodyFromSlice(operation *spec.Operation, sliceType reflect.Type,
 
 	if elementType.Kind() == reflect.Struct && elementType != reflect.TypeOf(struct{}{}) {
 		// For struct elements (e.g., []models.User), create a reference to the definition
-		schemaName := elementType.Name()
+		schemaName := definitionName(elementType)
 
 		// Generate schema definition for the element type
 		schema := generateSchemaFromStructWithDefinitions(elementType, swagger.Definitions)
@@ -415,6 +675,149 @@ func addResponseBodyFromSlice(operation *spec.Operation, sliceType reflect.Type,
 	}
 }
 
+// streamItemType returns T when t is an iter.Seq2[T, error] (func(yield func(T, error) bool))
+func streamItemType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 {
+		return nil, false
+	}
+
+	yield := t.In(0)
+	if yield.Kind() != reflect.Func || yield.NumIn() != 2 || yield.NumOut() != 1 {
+		return nil, false
+	}
+	if yield.In(1) != reflect.TypeOf((*error)(nil)).Elem() || yield.Out(0).Kind() != reflect.Bool {
+		return nil, false
+	}
+
+	return yield.In(0), true
+}
+
+// addResponseBodyFromStream documents a streamed list as {"data": [...], "count": N}
+func addResponseBodyFromStream(operation *spec.Operation, itemType reflect.Type, swagger *spec.Swagger) {
+	// Reuse the slice handling for the item schema, then wrap it in the list envelope
+	addResponseBodyFromSlice(operation, reflect.SliceOf(itemType), swagger)
+	arraySchema := operation.Responses.StatusCodeResponses[200].Schema
+
+	operation.Responses.StatusCodeResponses[200] = spec.Response{
+		ResponseProps: spec.ResponseProps{
+			Description: "Success (streamed; NDJSON yields one item per line)",
+			Schema: &spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type: []string{"object"},
+					Properties: map[string]spec.Schema{
+						"data":  *arraySchema,
+						"count": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// sseEventDataType returns T when t is a core.SSEStream[T]
+// (func(context.Context, string, func(core.SSEEvent[T]) error) error)
+func sseEventDataType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Func || t.NumIn() != 3 || t.In(2).Kind() != reflect.Func || t.In(2).NumIn() != 1 {
+		return nil, false
+	}
+
+	eventType := t.In(2).In(0)
+	if eventType.Kind() != reflect.Struct || eventType.PkgPath() != reflect.TypeOf(core.APIError{}).PkgPath() {
+		return nil, false
+	}
+	dataField, ok := eventType.FieldByName("Data")
+	if !ok {
+		return nil, false
+	}
+
+	return dataField.Type, true
+}
+
+// addResponseBodyFromSSE documents an event stream whose events carry T as JSON data
+func addResponseBodyFromSSE(operation *spec.Operation, dataType reflect.Type, swagger *spec.Swagger) {
+	var dataSchema spec.Schema
+	if dataType.Kind() == reflect.Struct && dataType.String() != "time.Time" && dataType.String() != "uuid.UUID" {
+		schemaName := definitionName(dataType)
+		schema := generateSchemaFromStructWithDefinitions(dataType, swagger.Definitions)
+		swagger.Definitions[schemaName] = *schema
+		dataSchema = spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Ref: spec.MustCreateRef(fmt.Sprintf("#/definitions/%s", schemaName)),
+			},
+		}
+	} else {
+		dataSchema = spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type:   []string{getSwaggerType(dataType)},
+				Format: getSwaggerFormat(dataType),
+			},
+		}
+	}
+
+	operation.Responses.StatusCodeResponses[200] = spec.Response{
+		ResponseProps: spec.ResponseProps{
+			Description: "Server-Sent Events stream; each event's data field is the JSON-encoded schema",
+			Schema:      &dataSchema,
+		},
+	}
+}
+
+// definitionName returns the definitions key for a struct type.
+// Generic instantiations, whose reflect names embed full package paths
+// (e.g. "Page[github.com/acme/app/models.User]"), become "PageOfUser".
+func definitionName(t reflect.Type) string {
+	name := t.Name()
+	base, args, ok := strings.Cut(name, "[")
+	if !ok {
+		return name
+	}
+
+	var parts []string
+	for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+		arg = strings.TrimLeft(strings.TrimSpace(arg), "[]*")
+		if dot := strings.LastIndex(arg, "."); dot != -1 {
+			arg = arg[dot+1:]
+		}
+		parts = append(parts, strings.ToUpper(arg[:1])+arg[1:])
+	}
+	return base + "Of" + strings.Join(parts, "And")
+}
+
+// isPageType reports whether t is a core.Page[T] instantiation
+func isPageType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct &&
+		t.PkgPath() == reflect.TypeOf(core.APIError{}).PkgPath() &&
+		strings.HasPrefix(t.Name(), "Page[")
+}
+
+// responseBodyType returns T when t is a handler.Response[T] instantiation
+func responseBodyType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Struct ||
+		t.PkgPath() != reflect.TypeOf(handler.RouteInfo{}).PkgPath() ||
+		!strings.HasPrefix(t.Name(), "Response[") {
+		return nil, false
+	}
+	field, ok := t.FieldByName("Body")
+	if !ok {
+		return nil, false
+	}
+	return field.Type, true
+}
+
+// addPageLinkHeader documents the RFC 8288 Link header written with paginated responses
+func addPageLinkHeader(operation *spec.Operation) {
+	response := operation.Responses.StatusCodeResponses[200]
+	response.Headers = map[string]spec.Header{
+		"Link": {
+			HeaderProps: spec.HeaderProps{
+				Description: `RFC 8288 links to neighbouring pages (rel="first", "prev", "next", "last")`,
+			},
+			SimpleSchema: spec.SimpleSchema{Type: "string"},
+		},
+	}
+	operation.Responses.StatusCodeResponses[200] = response
+}
+
 // generateSchemaFromStruct creates a Swagger schema from a Go struct
 func generateSchemaFromStruct(structType reflect.Type) *spec.Schema {
 	return generateSchemaFromStructWithDefinitions(structType, nil)
@@ -535,7 +938,7 @@ func createPropertySchema(field reflect.StructField, definitions map[string]spec
 			elementType.String() != "time.Time" &&
 			elementType.String() != "uuid.UUID" {
 
-			schemaName := elementType.Name()
+			schemaName := definitionName(elementType)
 
 			// Add to definitions if we have a definitions map and the type has a name
 			if definitions != nil && schemaName != "" {
@@ -584,7 +987,7 @@ func createPropertySchema(field reflect.StructField, definitions map[string]spec
 		fieldType.String() != "time.Time" &&
 		fieldType.String() != "uuid.UUID" {
 
-		schemaName := fieldType.Name()
+		schemaName := definitionName(fieldType)
 
 		// Only add to definitions if we have a definitions map and the type has a name
 		if definitions != nil && schemaName != "" {
@@ -776,6 +1179,11 @@ func isRequired(field reflect.StructField) bool {
 }
 
 func getSwaggerType(t reflect.Type) string {
+	// Optional values (e.g. *int) are documented as their element type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
 	// Check for special types first (before checking Kind, since uuid.UUID is [16]byte)
 	if t.String() == "time.Time" || t.String() == "uuid.UUID" {
 		return "string"
@@ -806,6 +1214,10 @@ func getSwaggerType(t reflect.Type) string {
 }
 
 func getSwaggerFormat(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
 	switch t.Kind() {
 	case reflect.Int32:
 		return "int32"
@@ -828,6 +1240,27 @@ func getSwaggerFormat(t reflect.Type) string {
 	}
 }
 
+// generateProduces lists the response media types for a route.
+// Explicit RouteInfo.Produces wins; negotiated, streamed and SSE responses list their formats.
+func generateProduces(route handler.PendingRoute) []string {
+	if len(route.RouteInfo.Produces) > 0 {
+		return route.RouteInfo.Produces
+	}
+
+	for _, middlewareName := range route.MiddlewareNames {
+		switch middlewareName {
+		case "ResponseNegotiated":
+			return core.DefaultEncoders.MediaTypes()
+		case "ResponseStream", "ResponseStreamWith":
+			return []string{"application/json", core.NDJSONContentType}
+		case "ResponseSSE", "ResponseSSEWith":
+			return []string{core.EventStreamContentType}
+		}
+	}
+
+	return []string{"application/json"}
+}
+
 func requiresAuth(route handler.PendingRoute) bool {
 	// Check if RequireAuth middleware is present in the middleware chain
 	for _, middlewareName := range route.MiddlewareNames {
@@ -883,6 +1316,34 @@ func addStandardResponses(operation *spec.Operation, swagger *spec.Swagger) {
 	}
 }
 
+// addErrorCodeResponses documents RouteInfo.ErrorCodes on the matching status responses.
+// Codes missing from the catalog are skipped since their status is unknown.
+func addErrorCodeResponses(operation *spec.Operation, route handler.PendingRoute, catalog *core.ErrorCatalog) {
+	if catalog == nil || len(route.RouteInfo.ErrorCodes) == 0 {
+		return
+	}
+
+	// Group declared codes by HTTP status
+	codesByStatus := make(map[int][]string)
+	for _, code := range route.RouteInfo.ErrorCodes {
+		def, ok := catalog.Lookup(code)
+		if !ok {
+			continue
+		}
+		codesByStatus[def.Status] = append(codesByStatus[def.Status], def.Code)
+	}
+
+	for status, codes := range codesByStatus {
+		response, exists := operation.Responses.StatusCodeResponses[status]
+		if !exists || response.Description == "" {
+			response.Description = http.StatusText(status)
+		}
+		response.Description = fmt.Sprintf("%s (error codes: %s)", response.Description, strings.Join(codes, ", "))
+		response.AddExtension("x-error-codes", codes)
+		operation.Responses.StatusCodeResponses[status] = response
+	}
+}
+
 // GenerateJSON returns the OpenAPI spec as JSON
 func GenerateJSON(registry *handler.Registry) ([]byte, error) {
 	spec := GenerateSpec(registry)
@@ -0,0 +1,246 @@
+package swagger
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-openapi/spec"
+	"github.com/platform-smith-labs/japi-core/v3/core"
+	"github.com/platform-smith-labs/japi-core/v3/handler"
+)
+
+// TestGenerateSpecWithCatalog verifies declared error codes are documented per status and the catalog is published
+func TestGenerateSpecWithCatalog(t *testing.T) {
+	catalog := core.NewErrorCatalog()
+	catalog.MustRegister(
+		core.ErrorDefinition{Code: "user.email_taken", Status: http.StatusConflict, Message: "Email is already registered"},
+		core.ErrorDefinition{Code: "user.invalid_email", Status: http.StatusBadRequest, Message: "Email is invalid"},
+		core.ErrorDefinition{Code: "user.name_taken", Status: http.StatusConflict, Message: "Name is already taken"},
+	)
+
+	reg := handler.NewRegistry()
+	handler.MakeHandler(reg, handler.RouteInfo{
+		Method:     "POST",
+		Path:       "/users",
+		ErrorCodes: []string{"user.email_taken", "user.invalid_email", "user.name_taken", "user.unregistered"},
+	}, func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+		return struct{}{}, nil
+	})
+
+	swagger := GenerateSpecWithCatalog(reg, catalog)
+
+	defs, ok := swagger.Extensions["x-error-catalog"].([]core.ErrorDefinition)
+	if !ok || len(defs) != 3 || defs[0].Code != "user.email_taken" {
+		t.Errorf("Expected the catalog as x-error-catalog, got %#v", swagger.Extensions["x-error-catalog"])
+	}
+
+	responses := swagger.Paths.Paths["/users"].Post.Responses.StatusCodeResponses
+	conflict := responses[http.StatusConflict]
+	codes, ok := conflict.Extensions["x-error-codes"].([]string)
+	if !ok || len(codes) != 2 || codes[0] != "user.email_taken" || codes[1] != "user.name_taken" {
+		t.Errorf("Expected both 409 codes, got %#v", conflict.Extensions["x-error-codes"])
+	}
+	if conflict.Description != "Conflict (error codes: user.email_taken, user.name_taken)" {
+		t.Errorf("Unexpected 409 description %q", conflict.Description)
+	}
+	badRequest := responses[http.StatusBadRequest]
+	if codes, _ := badRequest.Extensions["x-error-codes"].([]string); len(codes) != 1 || codes[0] != "user.invalid_email" {
+		t.Errorf("Expected the 400 code, got %#v", badRequest.Extensions["x-error-codes"])
+	}
+	for status, response := range responses {
+		codes, _ := response.Extensions["x-error-codes"].([]string)
+		for _, code := range codes {
+			if code == "user.unregistered" {
+				t.Errorf("Expected unregistered codes to be skipped, found on %d", status)
+			}
+		}
+	}
+}
+
+// TestGenerateSpecWithoutCatalog verifies a nil or empty catalog adds no extensions
+func TestGenerateSpecWithoutCatalog(t *testing.T) {
+	reg := handler.NewRegistry()
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "GET", Path: "/users", ErrorCodes: []string{"user.not_found"}},
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, nil
+		})
+
+	for name, catalog := range map[string]*core.ErrorCatalog{"nil": nil, "empty": core.NewErrorCatalog()} {
+		swagger := GenerateSpecWithCatalog(reg, catalog)
+		if _, ok := swagger.Extensions["x-error-catalog"]; ok {
+			t.Errorf("%s: expected no x-error-catalog", name)
+		}
+		for status, response := range swagger.Paths.Paths["/users"].Get.Responses.StatusCodeResponses {
+			if _, ok := response.Extensions["x-error-codes"]; ok {
+				t.Errorf("%s: expected no x-error-codes on %d", name, status)
+			}
+		}
+	}
+}
+
+// TestAddConditionalResponses verifies caching headers go on the documented success status without inventing a 200
+func TestAddConditionalResponses(t *testing.T) {
+	route := handler.PendingRoute{
+		RouteInfo:       handler.RouteInfo{CacheControl: "private, max-age=60"},
+		MiddlewareNames: []string{"ResponseETag"},
+	}
+	newOperation := func(statuses ...int) *spec.Operation {
+		operation := &spec.Operation{OperationProps: spec.OperationProps{
+			Responses: &spec.Responses{ResponsesProps: spec.ResponsesProps{StatusCodeResponses: make(map[int]spec.Response)}},
+		}}
+		for _, status := range statuses {
+			operation.Responses.StatusCodeResponses[status] = spec.Response{ResponseProps: spec.ResponseProps{Description: http.StatusText(status)}}
+		}
+		return operation
+	}
+
+	operation := newOperation(http.StatusCreated, http.StatusBadRequest)
+	addConditionalResponses(operation, route)
+	responses := operation.Responses.StatusCodeResponses
+	if _, ok := responses[http.StatusOK]; ok {
+		t.Error("Expected no 200 response to be added")
+	}
+	if headers := responses[http.StatusCreated].Headers; headers["ETag"].Description == "" || headers["Cache-Control"].Description != "private, max-age=60" {
+		t.Errorf("Expected the headers on 201, got %v", headers)
+	}
+	if _, ok := responses[http.StatusNotModified]; !ok {
+		t.Error("Expected a 304 response")
+	}
+
+	operation = newOperation(http.StatusBadRequest)
+	addConditionalResponses(operation, route)
+	if _, ok := operation.Responses.StatusCodeResponses[http.StatusOK]; ok {
+		t.Error("Expected no 200 response without a documented success status")
+	}
+
+	// Routes declaring 204 document the headers there instead of on 200
+	reg := handler.NewRegistry()
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "PUT", Path: "/users/{id}", StatusCodes: []int{http.StatusNoContent}, CacheControl: "no-store"},
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, nil
+		})
+	responses = GenerateSpec(reg).Paths.Paths["/users/{id}"].Put.Responses.StatusCodeResponses
+	if _, ok := responses[http.StatusOK]; ok {
+		t.Error("Expected no 200 response on a 204 route")
+	}
+	if responses[http.StatusNoContent].Headers["Cache-Control"].Description != "no-store" {
+		t.Errorf("Expected Cache-Control on 204, got %v", responses[http.StatusNoContent].Headers)
+	}
+}
+
+// TestGenerateSpecDocumentsServedRoutes verifies the spec describes the routes RegisterWithRouter serves
+func TestGenerateSpecDocumentsServedRoutes(t *testing.T) {
+	type firstUser struct {
+		ID string `json:"id"`
+	}
+	type secondUser struct {
+		Name string `json:"name"`
+	}
+
+	reg := handler.NewRegistry()
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "GET", Path: "/users/{id}", Summary: "first"},
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (firstUser, error) {
+			w.Header().Set("X-Handler", "first")
+			return firstUser{}, nil
+		})
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "GET", Path: "/users/{id}", Summary: "second"},
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (secondUser, error) {
+			w.Header().Set("X-Handler", "second")
+			return secondUser{}, nil
+		})
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "GET", Path: "/users/{userID}", Summary: "third"},
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, nil
+		})
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "FETCH", Path: "/things"},
+		func(ctx handler.HandlerContext[struct{}, struct{}], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, nil
+		})
+
+	r := chi.NewRouter()
+	_ = reg.RegisterWithRouter(r, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/users/7", nil))
+
+	swagger := GenerateSpec(reg)
+	get := swagger.Paths.Paths["/users/{id}"].Get
+	if get == nil || get.Summary != rec.Header().Get("X-Handler") {
+		t.Errorf("Expected the spec to document the served %q handler, got %+v", rec.Header().Get("X-Handler"), get)
+	}
+	if _, ok := swagger.Paths.Paths["/users/{userID}"]; ok {
+		t.Error("Expected the skipped duplicate to be left out")
+	}
+	if _, ok := swagger.Paths.Paths["/things"]; ok {
+		t.Error("Expected the unknown method to be left out")
+	}
+}
+
+// TestGenerateSpecDocumentsVersionRequests verifies each version's parameters and body are documented
+func TestGenerateSpecDocumentsVersionRequests(t *testing.T) {
+	type userParams struct {
+		ID     string `param:"id" validate:"required"`
+		Fields string `query:"fields"`
+	}
+	type userParamsV2 struct {
+		ID string `param:"id" validate:"required"`
+	}
+	type updateUser struct {
+		Name string `json:"name"`
+	}
+	type updateUserV2 struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	}
+
+	reg := handler.NewRegistry()
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "PUT", Path: "/users/{id}", Version: "v1"},
+		func(ctx handler.HandlerContext[userParams, updateUser], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, nil
+		})
+	handler.MakeHandler(reg, handler.RouteInfo{Method: "PUT", Path: "/users/{id}", Version: "v2"},
+		func(ctx handler.HandlerContext[userParamsV2, updateUserV2], w http.ResponseWriter, r *http.Request) (struct{}, error) {
+			return struct{}{}, nil
+		})
+
+	put := GenerateSpec(reg).Paths.Paths["/users/{id}"].Put
+	if put == nil {
+		t.Fatal("Expected a PUT operation")
+	}
+	parameters := make(map[string]spec.Parameter)
+	for _, parameter := range put.Parameters {
+		parameters[parameter.In+":"+parameter.Name] = parameter
+	}
+	if !parameters["path:id"].Required {
+		t.Error("Expected the id path parameter shared by both versions to stay required")
+	}
+	if fields, ok := parameters["query:fields"]; !ok || fields.Required {
+		t.Errorf("Expected the v1-only fields parameter to be documented as optional, got %+v", fields)
+	}
+	body, ok := parameters["body:body"]
+	if !ok || body.Schema == nil || len(body.Schema.OneOf) != 2 {
+		t.Fatalf("Expected the body to be a oneOf of both versions' schemas, got %+v", body)
+	}
+	refs := []string{body.Schema.OneOf[0].Ref.String(), body.Schema.OneOf[1].Ref.String()}
+	if !strings.HasSuffix(refs[0], "updateUser") || !strings.HasSuffix(refs[1], "updateUserV2") {
+		t.Errorf("Expected the v1 and v2 body schemas, got %v", refs)
+	}
+
+	versions, ok := put.Extensions["x-api-versions"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected x-api-versions, got %v", put.Extensions)
+	}
+	v2Parameters, ok := versions["v2"].(map[string]any)["parameters"].([]spec.Parameter)
+	if !ok || len(v2Parameters) != 2 {
+		t.Fatalf("Expected the v2 id and body parameters, got %v", versions["v2"])
+	}
+	for _, parameter := range v2Parameters {
+		if parameter.In == "body" && !strings.HasSuffix(parameter.Schema.Ref.String(), "updateUserV2") {
+			t.Errorf("Expected the v2 body schema under x-api-versions, got %s", parameter.Schema.Ref.String())
+		}
+	}
+}